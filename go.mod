@@ -1,3 +1,7 @@
 module github.com/rgonzalezNetel/rlib
 
 go 1.22.2
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require github.com/andybalholm/brotli v1.2.5
@@ -0,0 +1,71 @@
+package respondwithjson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Responder con el formato JSON y la cabecera ETag (SHA-256 del cuerpo).
+// En peticiones GET/HEAD cuyo If-None-Match coincide con el ETag se responde 304 Not Modified sin cuerpo.
+// El hash se calcula antes de añadir el timestamp de IncludeTimestamp, que cambiaría el ETag en cada segundo.
+func RespondWithJSONETag(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	buf, err := encodeJSON(response)
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer func() { putBuffer(buf) }()
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("ETag", etag)
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && statusCode >= 200 && statusCode < 300 &&
+		etagMatches(r.Header.Get("If-None-Match"), etag) {
+		writeHeader(w, http.StatusNotModified)
+		return nil
+	}
+
+	if prepared := prepareResponse(response); prepared.Timestamp != response.Timestamp {
+		stamped, err := encodeJSON(prepared)
+		if err != nil {
+			writeEncodeFailure(w)
+			return err
+		}
+		putBuffer(buf)
+		buf = stamped
+	}
+	_, err = writeBody(w, statusCode, "application/json", buf.Bytes())
+	return err
+}
+
+// etagMatches indica si la cabecera If-None-Match contiene el ETag (comparación débil) o es "*"
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Responder con el formato JSON y las cabeceras de caché Cache-Control y Expires calculadas a partir del ttl.
+// Un ttl de cero (o negativo) envía Cache-Control: no-store.
+func RespondWithJSONCache(w http.ResponseWriter, statusCode int, response JsonResponse, ttl time.Duration) error {
+	if ttl <= 0 {
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		// Se redondea hacia arriba para que un ttl menor de un segundo no envíe max-age=0
+		seconds := int64(math.Ceil(ttl.Seconds()))
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.FormatInt(seconds, 10))
+		w.Header().Set("Expires", Now().Add(ttl).UTC().Format(http.TimeFormat))
+	}
+	return RespondWithJSONErr(w, statusCode, response)
+}
@@ -0,0 +1,85 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// CapturingWriter envuelve un http.ResponseWriter y guarda el código de estado y una copia del cuerpo escrito.
+// Sirve para comprobar lo que produce un handler o para que un middleware inspeccione la respuesta después.
+// Si se crea con un writer nil sólo graba la respuesta, sin enviarla a ningún sitio.
+type CapturingWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// Constructor para CapturingWriter. Ejemplo: cw := NewCapturingWriter(w); next.ServeHTTP(cw, r)
+func NewCapturingWriter(w http.ResponseWriter) *CapturingWriter {
+	cw := &CapturingWriter{w: w}
+	if w == nil {
+		cw.header = make(http.Header)
+	}
+	return cw
+}
+
+// Header devuelve las cabeceras del writer envuelto
+func (c *CapturingWriter) Header() http.Header {
+	if c.w == nil {
+		return c.header
+	}
+	return c.w.Header()
+}
+
+// WriteHeader guarda el primer código de estado y lo envía al writer envuelto
+func (c *CapturingWriter) WriteHeader(statusCode int) {
+	if c.wroteHeader {
+		return
+	}
+	c.status = statusCode
+	c.wroteHeader = true
+	if c.w != nil {
+		c.w.WriteHeader(statusCode)
+	}
+}
+
+// Write guarda una copia de los datos y los escribe en el writer envuelto. Si no se ha enviado
+// el código de estado se usa 200, igual que net/http
+func (c *CapturingWriter) Write(p []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.w == nil {
+		return c.body.Write(p)
+	}
+	n, err := c.w.Write(p)
+	c.body.Write(p[:n])
+	return n, err
+}
+
+// Flush envía los datos al cliente si el writer envuelto implementa http.Flusher
+func (c *CapturingWriter) Flush() {
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap devuelve el writer envuelto para http.ResponseController
+func (c *CapturingWriter) Unwrap() http.ResponseWriter {
+	return c.w
+}
+
+// Status devuelve el código de estado enviado, o 200 si todavía no se ha escrito nada
+func (c *CapturingWriter) Status() int {
+	if !c.wroteHeader {
+		return http.StatusOK
+	}
+	return c.status
+}
+
+// Body devuelve una copia del cuerpo escrito hasta el momento
+func (c *CapturingWriter) Body() []byte {
+	return bytes.Clone(c.body.Bytes())
+}
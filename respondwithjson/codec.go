@@ -0,0 +1,60 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// Encoder es lo que el paquete necesita de un encoder JSON; *json.Encoder lo implementa.
+// Si además tiene SetIndent(prefix, indent string) se usa para la sangría de PrettyPrint.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// indentSetter lo implementan los encoders que admiten sangría (ej. *json.Encoder)
+type indentSetter interface {
+	SetIndent(prefix, indent string)
+}
+
+// htmlEscapeSetter lo implementan los encoders que permiten activar el escape de HTML (ej. *json.Encoder)
+type htmlEscapeSetter interface {
+	SetEscapeHTML(on bool)
+}
+
+// Marshal es la función con la que el paquete codifica los valores en JSON (por defecto json.Marshal).
+// Se puede sustituir por otra implementación compatible (ej. jsoniter o go-json) antes de atender peticiones.
+var Marshal func(v interface{}) ([]byte, error) = json.Marshal
+
+// NewEncoder crea los encoders con los que se escriben las respuestas (por defecto json.NewEncoder).
+// Igual que Marshal, se debe sustituir antes de atender peticiones. Sólo los encoders de la función por defecto
+// se reutilizan entre respuestas; con un NewEncoder propio se crea uno por respuesta.
+var NewEncoder func(w io.Writer) Encoder = defaultNewEncoder
+
+// defaultNewEncoder es json.NewEncoder; es la única función cuyos encoders se guardan en encoderPool
+func defaultNewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+// defaultNewEncoderPointer identifica defaultNewEncoder. Ninguna función de fuera del paquete puede
+// tener la misma dirección, así que la comparación no confunde un NewEncoder propio con el de por defecto.
+var defaultNewEncoderPointer = reflect.ValueOf(defaultNewEncoder).Pointer()
+
+// usesDefaultEncoder indica si NewEncoder sigue siendo la función por defecto
+func usesDefaultEncoder() bool {
+	return reflect.ValueOf(NewEncoder).Pointer() == defaultNewEncoderPointer
+}
+
+// marshalIndent es json.MarshalIndent (sin prefijo) usando Marshal
+func marshalIndent(v interface{}, indent string) ([]byte, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Codificaciones de contenido soportadas (Content-Encoding)
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// CompressionMinSize es el tamaño mínimo en bytes del cuerpo a partir del cual se comprime la respuesta;
+// los cuerpos más pequeños se envían sin comprimir
+var CompressionMinSize = 1024
+
+// gzipWriterPool reutiliza los gzip.Writer entre peticiones
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// brotliWriterPool reutiliza los brotli.Writer entre peticiones
+var brotliWriterPool = sync.Pool{
+	New: func() interface{} {
+		return brotli.NewWriter(io.Discard)
+	},
+}
+
+// Responder con el formato JSON comprimido con gzip si el cliente lo admite (Accept-Encoding).
+// Si el cliente no admite gzip, o el cuerpo es menor que CompressionMinSize, se responde sin comprimir.
+func RespondWithJSONGzip(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding := ""
+	if acceptsEncoding(r, encodingGzip) {
+		encoding = encodingGzip
+	}
+	return respondCompressed(w, statusCode, response, encoding)
+}
+
+// Responder con el formato JSON comprimido con la mejor codificación que admita el cliente:
+// brotli (br) si aparece en Accept-Encoding, si no gzip, y si no sin comprimir. Los cuerpos menores que
+// CompressionMinSize se envían sin comprimir.
+func RespondWithJSONCompressed(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	w.Header().Add("Vary", "Accept-Encoding")
+	return respondCompressed(w, statusCode, response, selectEncoding(r))
+}
+
+// selectEncoding elige la codificación de la respuesta según Accept-Encoding: br, gzip o "" (sin comprimir)
+func selectEncoding(r *http.Request) string {
+	switch {
+	case acceptsEncoding(r, encodingBrotli):
+		return encodingBrotli
+	case acceptsEncoding(r, encodingGzip):
+		return encodingGzip
+	}
+	return ""
+}
+
+// respondCompressed codifica la respuesta, la comprime con encoding y la escribe con su Content-Length
+func respondCompressed(w http.ResponseWriter, statusCode int, response JsonResponse, encoding string) error {
+	if encoding == "" {
+		return RespondWithJSONErr(w, statusCode, response)
+	}
+
+	buf, err := encodeJSON(prepareResponse(response))
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer putBuffer(buf)
+
+	// Comprimir las respuestas pequeñas cuesta CPU y puede incluso aumentar su tamaño
+	if buf.Len() < CompressionMinSize {
+		_, err = writeBody(w, statusCode, "application/json", buf.Bytes())
+		return err
+	}
+
+	compressed := bufferPool.Get().(*bytes.Buffer)
+	compressed.Reset()
+	defer putBuffer(compressed)
+	if err := compressBody(compressed, encoding, buf.Bytes()); err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+
+	// La longitud se calcula después de comprimir
+	w.Header().Set("Content-Encoding", encoding)
+	_, err = writeBody(w, statusCode, "application/json", compressed.Bytes())
+	return err
+}
+
+// compressBody comprime body con la codificación indicada y escribe el resultado en dst
+func compressBody(dst *bytes.Buffer, encoding string, body []byte) error {
+	var cw io.WriteCloser
+	switch encoding {
+	case encodingGzip:
+		gz := gzipWriterPool.Get().(*gzip.Writer)
+		defer gzipWriterPool.Put(gz)
+		gz.Reset(dst)
+		cw = gz
+	case encodingBrotli:
+		br := brotliWriterPool.Get().(*brotli.Writer)
+		defer brotliWriterPool.Put(br)
+		br.Reset(dst)
+		cw = br
+	default:
+		return fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+
+	if _, err := cw.Write(body); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// acceptsEncoding indica si la cabecera Accept-Encoding de la petición incluye la codificación indicada
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(name), encoding) {
+			return qValue(params) > 0
+		}
+	}
+	return false
+}
+
+// qValue devuelve el peso q de los parámetros de una cabecera (por defecto 1). q=0 indica rechazo explícito
+func qValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.TrimSpace(key) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
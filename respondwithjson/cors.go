@@ -0,0 +1,64 @@
+package respondwithjson
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSConfig contiene la política CORS que se aplica a las respuestas
+type CORSConfig struct {
+	AllowedOrigins   []string // Orígenes permitidos; "*" permite cualquiera
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+}
+
+// Responder con el formato JSON añadiendo las cabeceras Access-Control-Allow-* según el Origin de la petición.
+// Si el origen no está en la lista no se añade ninguna cabecera CORS. Con credenciales no se puede usar "*",
+// por lo que en ese caso se devuelve el propio origen de la petición.
+func RespondWithJSONCORS(w http.ResponseWriter, r *http.Request, cfg CORSConfig, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	setCORSHeaders(w, r, cfg)
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// setCORSHeaders escribe las cabeceras CORS si el origen de la petición está permitido
+func setCORSHeaders(w http.ResponseWriter, r *http.Request, cfg CORSConfig) {
+	w.Header().Add("Vary", "Origin")
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+
+	wildcard := false
+	allowed := false
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			wildcard = true
+			allowed = true
+			break
+		}
+		if strings.EqualFold(o, origin) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return
+	}
+
+	if wildcard && !cfg.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	if cfg.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(cfg.AllowedMethods) > 0 {
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+	}
+	if len(cfg.AllowedHeaders) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+	}
+}
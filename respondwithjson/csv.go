@@ -0,0 +1,93 @@
+package respondwithjson
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+// Responder con un fichero CSV para descargar (Content-Disposition: attachment) con las filas indicadas
+func RespondWithCSV(w http.ResponseWriter, filename string, rows [][]string) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	writeHeader(w, http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Responder con un fichero CSV generado a partir de un slice de estructuras (o punteros a estructuras).
+// La cabecera se obtiene de las etiquetas json de los campos y cada elemento genera una fila.
+func RespondWithCSVFromStructs(w http.ResponseWriter, filename string, slice interface{}) error {
+	rows, err := structsToCSVRows(slice)
+	if err != nil {
+		return err
+	}
+	return RespondWithCSV(w, filename, rows)
+}
+
+// structsToCSVRows convierte el slice de estructuras en filas CSV, con la fila de cabecera primero
+func structsToCSVRows(slice interface{}) ([][]string, error) {
+	val := reflect.ValueOf(slice)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unsupported type for CSV: %s", val.Kind())
+	}
+
+	elemType := val.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported element type for CSV: %s", elemType.Kind())
+	}
+
+	var indexes []int
+	var header []string
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		indexes = append(indexes, i)
+		header = append(header, fieldName(field))
+	}
+
+	rows := [][]string{header}
+	for i := 0; i < val.Len(); i++ {
+		elem := val.Index(i)
+		for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		row := make([]string, len(indexes))
+		if elem.Kind() == reflect.Struct {
+			for j, index := range indexes {
+				row[j] = formatCSVValue(elem.Field(index))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// formatCSVValue convierte el valor de un campo en el texto de la celda
+func formatCSVValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
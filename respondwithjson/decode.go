@@ -0,0 +1,293 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodyBytes es el tamaño máximo del cuerpo que acepta CheckAndRespondJSON (1 MB)
+var DefaultMaxBodyBytes int64 = 1 << 20
+
+// ErrBodyTooLarge se devuelve cuando el cuerpo de la petición supera el límite permitido (responder con 413)
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// ErrEmptyBody se devuelve cuando la petición no tiene cuerpo (responder con 400)
+var ErrEmptyBody = errors.New("request body is empty")
+
+// ErrUnsupportedMediaType se devuelve cuando el Content-Type de la petición no es application/json (responder con 415)
+var ErrUnsupportedMediaType = errors.New("content type must be application/json")
+
+// ErrUnknownField se devuelve cuando el JSON contiene un campo que no existe en la estructura.
+// Se puede obtener con errors.As para mostrar o registrar el nombre del campo.
+type ErrUnknownField struct {
+	Field string
+}
+
+// Error devuelve un mensaje que se puede mostrar al cliente
+func (e ErrUnknownField) Error() string {
+	return fmt.Sprintf("field %q is not allowed", e.Field)
+}
+
+// Verificar y responder con JSON correcto
+func CheckAndRespondJSON(w http.ResponseWriter, r *http.Request, object interface{}) error {
+	return CheckAndRespondJSONWithLimit(w, r, object, DefaultMaxBodyBytes)
+}
+
+// Igual que CheckAndRespondJSON pero limitando el cuerpo de la petición a maxBytes
+func CheckAndRespondJSONWithLimit(w http.ResponseWriter, r *http.Request, object interface{}, maxBytes int64) error {
+	return decodeRequest(w, r, object, maxBytes, true)
+}
+
+// Igual que CheckAndRespondJSON pero ignora los campos del JSON que no están en la estructura.
+// Útil en APIs públicas en las que los clientes pueden enviar campos más nuevos.
+func CheckAndRespondJSONLenient(w http.ResponseWriter, r *http.Request, object interface{}) error {
+	return decodeRequest(w, r, object, DefaultMaxBodyBytes, false)
+}
+
+// decodeRequest decodifica el cuerpo de la petición en object con el límite de tamaño indicado
+func decodeRequest(w http.ResponseWriter, r *http.Request, object interface{}, maxBytes int64, disallowUnknown bool) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ErrEmptyBody
+	}
+	// MaxBytesReader además indica al servidor que cierre la conexión si se supera el límite
+	return decodeJSON(http.MaxBytesReader(w, r.Body, maxBytes), object, maxBytes, disallowUnknown)
+}
+
+// DecodeJSONStrict decodifica JSON de cualquier reader (colas de mensajes, ficheros...) con las mismas reglas que
+// CheckAndRespondJSON: límite de DefaultMaxBodyBytes, rechazo de los campos desconocidos y errores legibles.
+func DecodeJSONStrict(r io.Reader, object interface{}) error {
+	return decodeJSON(r, object, DefaultMaxBodyBytes, true)
+}
+
+// decodeJSON decodifica un valor JSON del reader en object leyendo como máximo maxBytes
+func decodeJSON(r io.Reader, object interface{}, maxBytes int64, disallowUnknown bool) error {
+	if r == nil {
+		return ErrEmptyBody
+	}
+
+	decoder := json.NewDecoder(&limitedReader{r: r, n: maxBytes})
+	if disallowUnknown {
+		decoder.DisallowUnknownFields() // Evita la decodificación si JSON contiene campos que no están en la estructura
+	}
+	err := decoder.Decode(object)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || errors.Is(err, ErrBodyTooLarge) {
+			return ErrBodyTooLarge
+		}
+		// Un cuerpo sin ningún byte hace que el decoder devuelva io.EOF
+		if errors.Is(err, io.EOF) {
+			return ErrEmptyBody
+		}
+		return friendlyDecodeError(err)
+	}
+	return nil
+}
+
+// limitedReader lee como máximo n bytes y devuelve ErrBodyTooLarge si el reader tiene más datos
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// Se pide un byte más del límite para saber si hay más datos
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	k, err := l.r.Read(p)
+	if int64(k) <= l.n {
+		l.n -= int64(k)
+		l.err = err
+		return k, err
+	}
+	k = int(l.n)
+	l.n = 0
+	l.err = ErrBodyTooLarge
+	return k, l.err
+}
+
+// friendlyDecodeError convierte los errores de json.Decoder en mensajes que se pueden mostrar al usuario
+func friendlyDecodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("request body contains badly-formed JSON (at position %d)", syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("request body contains badly-formed JSON")
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Errorf("request body has an invalid value, expected %s", typeErr.Type)
+		}
+		return fmt.Errorf("field '%s' has an invalid type, expected %s", typeErr.Field, typeErr.Type)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		if unquoted, uerr := strconv.Unquote(field); uerr == nil {
+			field = unquoted
+		}
+		return ErrUnknownField{Field: field}
+	}
+	return err
+}
+
+// Igual que CheckAndRespondJSON pero antes comprueba que el Content-Type sea application/json (o +json).
+// Los parámetros como charset se ignoran; si falta la cabecera devuelve ErrUnsupportedMediaType.
+func CheckAndRespondJSONStrict(w http.ResponseWriter, r *http.Request, object interface{}) error {
+	if !isJSONContentType(r.Header.Get("Content-Type")) {
+		return ErrUnsupportedMediaType
+	}
+	return CheckAndRespondJSON(w, r, object)
+}
+
+// isJSONContentType indica si la cabecera Content-Type corresponde a application/json o a un tipo
+// con el sufijo +json (ej. application/vnd.api+json, application/merge-patch+json)
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// DecodeAndValidate decodifica el cuerpo de la petición igual que CheckAndRespondJSON y después
+// aplica Validate al objeto. No escribe nada en la respuesta: el llamador decide el código de estado.
+func DecodeAndValidate(w http.ResponseWriter, r *http.Request, object interface{}) error {
+	if err := CheckAndRespondJSON(w, r, object); err != nil {
+		return err
+	}
+	return Validate(object)
+}
+
+// Igual que DecodeAndValidate pero si falla responde directamente: 422 con el error de cada campo en details
+// (RespondWithValidationErrors) si falla la validación y el código registrado para el error (por defecto 400)
+// si falla la decodificación. Devuelve true si todo es correcto.
+func DecodeAndValidateOrRespond(w http.ResponseWriter, r *http.Request, object interface{}) bool {
+	if err := CheckAndRespondJSON(w, r, object); err != nil {
+		status := StatusForError(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest
+		}
+		RespondWithError(w, status, err)
+		return false
+	}
+	if err := Validate(object); err != nil {
+		fieldErrs := FieldErrors(err)
+		switch {
+		case errors.Is(err, ErrInvalidRule):
+			RespondWithError(w, http.StatusInternalServerError, err)
+		case len(fieldErrs) > 0:
+			RespondWithValidationErrors(w, fieldErrs)
+		default:
+			RespondWithError(w, http.StatusUnprocessableEntity, err)
+		}
+		return false
+	}
+	return true
+}
+
+// DecodeStream decodifica un flujo de objetos JSON (ej. NDJSON, un objeto por línea) del cuerpo de la petición
+// sin cargarlo entero en memoria. Para cada objeto llama a newElem para obtener el destino y a handle con el resultado.
+// Respeta DefaultMaxBodyBytes para el cuerpo completo y los errores indican el índice del elemento.
+// Un cuerpo vacío (o sólo con espacios) devuelve ErrEmptyBody y los datos sobrantes tras el último elemento, un error.
+func DecodeStream(r *http.Request, newElem func() interface{}, handle func(interface{}) error) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ErrEmptyBody
+	}
+
+	body := http.MaxBytesReader(nil, r.Body, DefaultMaxBodyBytes)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	index := 0
+	for ; decoder.More(); index++ {
+		elem := newElem()
+		if err := decoder.Decode(elem); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return fmt.Errorf("element %d: %w", index, ErrBodyTooLarge)
+			}
+			return fmt.Errorf("element %d: %w", index, friendlyDecodeError(err))
+		}
+		if err := handle(elem); err != nil {
+			return fmt.Errorf("element %d: %w", index, err)
+		}
+	}
+	// More se detiene en un } o ] sobrante; el flujo sólo es válido si después no queda nada
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			return fmt.Errorf("element %d: %w", index, ErrBodyTooLarge)
+		case err != nil:
+			return fmt.Errorf("element %d: %w", index, friendlyDecodeError(err))
+		}
+		return fmt.Errorf("element %d: request body contains badly-formed JSON", index)
+	}
+	if index == 0 {
+		return ErrEmptyBody
+	}
+	return nil
+}
+
+// DecodePatch decodifica el cuerpo de una petición PATCH en object y devuelve además las claves JSON del primer nivel
+// que estaban presentes, para aplicar sólo los campos que el cliente quiere cambiar (un campo ausente y uno con su
+// valor cero son indistinguibles en la estructura). Aplica DefaultMaxBodyBytes y rechaza los campos desconocidos.
+// Ejemplo: if present["email"] { user.Email = patch.Email }
+func DecodePatch(r *http.Request, object interface{}) (map[string]bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, ErrEmptyBody
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, DefaultMaxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrBodyTooLarge
+		}
+		return nil, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ErrEmptyBody
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, errors.New("request body must be a JSON object")
+		}
+		return nil, friendlyDecodeError(err)
+	}
+	if fields == nil {
+		return nil, errors.New("request body must be a JSON object")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(object); err != nil {
+		return nil, friendlyDecodeError(err)
+	}
+
+	present := make(map[string]bool, len(fields))
+	for key := range fields {
+		present[key] = true
+	}
+	return present, nil
+}
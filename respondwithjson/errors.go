@@ -0,0 +1,206 @@
+package respondwithjson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// NotFoundError lo implementan los errores que indican que el recurso no existe (404)
+type NotFoundError interface {
+	error
+	NotFound() bool
+}
+
+// UnauthorizedError lo implementan los errores de autenticación (401)
+type UnauthorizedError interface {
+	error
+	Unauthorized() bool
+}
+
+// ForbiddenError lo implementan los errores de permisos (403)
+type ForbiddenError interface {
+	error
+	Forbidden() bool
+}
+
+// ConflictError lo implementan los errores de conflicto con el estado del recurso (409)
+type ConflictError interface {
+	error
+	Conflict() bool
+}
+
+// StatusCoder lo implementan los errores que indican su propio código de estado HTTP
+type StatusCoder interface {
+	error
+	HTTPStatus() int
+}
+
+// StatusClientClosedRequest es el código no estándar (499, de nginx) para las peticiones que el cliente canceló
+const StatusClientClosedRequest = 499
+
+// HTTPError es un error que lleva su propio código de estado HTTP y código de error
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+// Constructor para HTTPError. Ejemplo: return NewHTTPError(404, "NOT_FOUND", "user not found", err)
+func NewHTTPError(status int, code, message string, err error) *HTTPError {
+	return &HTTPError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Error devuelve el mensaje; si está vacío, el del error envuelto o el texto del código de estado
+func (e HTTPError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return http.StatusText(e.Status)
+}
+
+// Unwrap devuelve el error envuelto para errors.Is y errors.As
+func (e HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus devuelve el código de estado del error
+func (e HTTPError) HTTPStatus() int {
+	return e.Status
+}
+
+// asHTTPError busca un HTTPError (por valor o por puntero) en la cadena del error
+func asHTTPError(err error) (HTTPError, bool) {
+	var ptr *HTTPError
+	if errors.As(err, &ptr) && ptr != nil {
+		return *ptr, true
+	}
+	var val HTTPError
+	if errors.As(err, &val) {
+		return val, true
+	}
+	return HTTPError{}, false
+}
+
+// Función para enviar una respuesta con el código de estado, el código de error y el mensaje del HTTPError.
+// Si el HTTPError no tiene código de estado se responde 500.
+func RespondWithHTTPError(w http.ResponseWriter, e HTTPError) {
+	respondWithHTTPError(w, e, http.StatusInternalServerError, "")
+}
+
+// respondWithHTTPError envía la respuesta del HTTPError con el mensaje en el idioma lang;
+// fallbackStatus se usa cuando el HTTPError no tiene código de estado
+func respondWithHTTPError(w http.ResponseWriter, e HTTPError, fallbackStatus int, lang string) {
+	status := e.Status
+	if status == 0 {
+		status = fallbackStatus
+	}
+	logError(status, e)
+	response := NewJsonResponseWithStatus(StatusError, localizedMessage(MessageKeyError, lang), nil, e.Error())
+	response.Code = e.Code
+	RespondWithJSON(w, status, response)
+}
+
+// errorStatus asocia un error con un código de estado HTTP
+type errorStatus struct {
+	target error
+	status int
+}
+
+var (
+	errorStatusMu sync.RWMutex
+	errorStatuses = []errorStatus{
+		{ErrEmptyBody, http.StatusBadRequest},
+		{ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+		{ErrUnsupportedMediaType, http.StatusUnsupportedMediaType},
+	}
+)
+
+// RegisterErrorStatus asocia un error (comparado con errors.Is) con el código de estado para RespondWithMappedError.
+// Los registros posteriores tienen prioridad sobre los anteriores.
+func RegisterErrorStatus(target error, status int) {
+	errorStatusMu.Lock()
+	defer errorStatusMu.Unlock()
+	errorStatuses = append(errorStatuses, errorStatus{target, status})
+}
+
+// StatusForError devuelve el código de estado registrado para el error recorriendo su cadena con errors.Is y errors.As.
+// Un HTTPError o un error con el método HTTPStatus() en la cadena tiene prioridad; después se buscan los errores
+// registrados, context.DeadlineExceeded (504), context.Canceled (499) y las interfaces NotFoundError, etc.
+// Si no hay ninguno devuelve 500.
+func StatusForError(err error) int {
+	if httpErr, ok := asHTTPError(err); ok && httpErr.Status != 0 {
+		return httpErr.Status
+	}
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		if status := coder.HTTPStatus(); status != 0 {
+			return status
+		}
+	}
+
+	errorStatusMu.RLock()
+	for i := len(errorStatuses) - 1; i >= 0; i-- {
+		if errors.Is(err, errorStatuses[i].target) {
+			errorStatusMu.RUnlock()
+			return errorStatuses[i].status
+		}
+	}
+	errorStatusMu.RUnlock()
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	}
+
+	var notFound NotFoundError
+	var unauthorized UnauthorizedError
+	var forbidden ForbiddenError
+	var conflict ConflictError
+	var validation ValidationErrors
+	var unknownField ErrUnknownField
+	var invalidQuery ErrInvalidQueryParam
+	switch {
+	case errors.As(err, &notFound) && notFound.NotFound():
+		return http.StatusNotFound
+	case errors.As(err, &unauthorized) && unauthorized.Unauthorized():
+		return http.StatusUnauthorized
+	case errors.As(err, &forbidden) && forbidden.Forbidden():
+		return http.StatusForbidden
+	case errors.As(err, &conflict) && conflict.Conflict():
+		return http.StatusConflict
+	case errors.As(err, &validation):
+		return http.StatusUnprocessableEntity
+	case errors.As(err, &unknownField), errors.As(err, &invalidQuery):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Función para enviar una respuesta con el error usando el código de estado registrado para él (por defecto 500).
+// Si err es nil no escribe nada.
+func RespondWithMappedError(w http.ResponseWriter, err error) {
+	RespondFromError(w, err)
+}
+
+// Función para enviar una respuesta con el error deduciendo el código de estado con StatusForError:
+// HTTPStatus() del error, errores registrados, 504 si se agotó el plazo del contexto, 499 si se canceló, o 500.
+// Si err es nil no escribe nada.
+func RespondFromError(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	RespondWithError(w, StatusForError(err), err)
+}
@@ -0,0 +1,21 @@
+package respondwithjson
+
+import (
+	"mime"
+	"net/http"
+)
+
+// Responder con un cuerpo binario (ej. PDF o imagen) con el Content-Type indicado y su Content-Length
+func RespondWithBytes(w http.ResponseWriter, statusCode int, contentType string, body []byte) error {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	_, err := writeBody(w, statusCode, contentType, body)
+	return err
+}
+
+// Responder con un fichero para descargar (Content-Disposition: attachment) con el nombre indicado
+func RespondWithDownload(w http.ResponseWriter, filename, contentType string, body []byte) error {
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	return RespondWithBytes(w, http.StatusOK, contentType, body)
+}
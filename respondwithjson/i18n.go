@@ -0,0 +1,73 @@
+package respondwithjson
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Claves de los mensajes estándar que usan los helpers
+const (
+	MessageKeySuccess = "success"
+	MessageKeyError   = "error"
+)
+
+// MessageProvider traduce los mensajes estándar (MessageKeySuccess, MessageKeyError) al idioma indicado.
+// lang es la etiqueta de idioma de Accept-Language (ej. "es", "en-US") o "" si no se conoce.
+type MessageProvider interface {
+	Message(key, lang string) string
+}
+
+// Messages es el proveedor de mensajes que usan los helpers. Por defecto devuelve SuccessMessage y ErrorMessage
+var Messages MessageProvider = DefaultMessageProvider{}
+
+// DefaultMessageProvider devuelve siempre SuccessMessage y ErrorMessage, sin importar el idioma
+type DefaultMessageProvider struct{}
+
+// Message devuelve el mensaje configurado para la clave, o la propia clave si no se conoce
+func (DefaultMessageProvider) Message(key, lang string) string {
+	switch key {
+	case MessageKeySuccess:
+		return SuccessMessage
+	case MessageKeyError:
+		return ErrorMessage
+	}
+	return key
+}
+
+// localizedMessage devuelve el mensaje de la clave según el proveedor configurado
+func localizedMessage(key, lang string) string {
+	if Messages == nil {
+		return DefaultMessageProvider{}.Message(key, lang)
+	}
+	return Messages.Message(key, lang)
+}
+
+// requestLanguage devuelve el idioma preferido de la cabecera Accept-Language de la petición
+func requestLanguage(r *http.Request) string {
+	var lang string
+	var best float64
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		if q := qValue(params); q > best {
+			lang, best = tag, q
+		}
+	}
+	return lang
+}
+
+// Igual que RespondWithSuccess pero con el mensaje traducido al idioma de Accept-Language
+func RespondWithSuccessLang(w http.ResponseWriter, r *http.Request, data interface{}) {
+	w = skipBodyForHead(w, r)
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, requestLanguage(r)), data, "")
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// Igual que RespondWithError pero con el mensaje traducido al idioma de Accept-Language
+func RespondWithErrorLang(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	w = skipBodyForHead(w, r)
+	respondWithError(w, statusCode, err, requestLanguage(r))
+}
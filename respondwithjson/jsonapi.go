@@ -0,0 +1,71 @@
+package respondwithjson
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// JSONAPIContentType es el tipo de contenido de las respuestas JSON:API (https://jsonapi.org)
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIDocument es el documento de nivel superior de JSON:API: lleva data o errors, nunca los dos
+type JSONAPIDocument struct {
+	Data   *JSONAPIResource `json:"data,omitempty"`
+	Errors []JSONAPIError   `json:"errors,omitempty"`
+}
+
+// JSONAPIResource es un objeto de recurso de JSON:API
+type JSONAPIResource struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id,omitempty"`
+	Attributes interface{} `json:"attributes,omitempty"`
+}
+
+// JSONAPIError es un objeto de error de JSON:API. Status es el código HTTP como string (ej. "422")
+type JSONAPIError struct {
+	Status string              `json:"status,omitempty"`
+	Code   string              `json:"code,omitempty"`
+	Title  string              `json:"title,omitempty"`
+	Detail string              `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+}
+
+// JSONAPIErrorSource indica el origen del error: un JSON Pointer al campo del documento (ej. "/data/attributes/email")
+// o el parámetro de la query que lo provocó
+type JSONAPIErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// Responder con un recurso en formato JSON:API y Content-Type application/vnd.api+json.
+// Ejemplo: RespondJSONAPI(w, http.StatusOK, "users", "42", user)
+func RespondJSONAPI(w http.ResponseWriter, statusCode int, resourceType string, id string, attributes interface{}) error {
+	doc := JSONAPIDocument{
+		Data: &JSONAPIResource{
+			Type:       resourceType,
+			ID:         id,
+			Attributes: attributes,
+		},
+	}
+	_, err := writeJSONAs(w, statusCode, JSONAPIContentType, doc)
+	return err
+}
+
+// Responder con errores en formato JSON:API. Los errores sin Status reciben el código de la respuesta
+// y los que no tienen Title el texto estándar de ese código.
+func RespondJSONAPIErrors(w http.ResponseWriter, statusCode int, errs ...JSONAPIError) error {
+	doc := JSONAPIDocument{Errors: make([]JSONAPIError, 0, len(errs))}
+	for _, e := range errs {
+		if e.Status == "" {
+			e.Status = strconv.Itoa(statusCode)
+		}
+		if e.Title == "" {
+			if status, err := strconv.Atoi(e.Status); err == nil {
+				e.Title = http.StatusText(status)
+			}
+		}
+		doc.Errors = append(doc.Errors, e)
+	}
+	_, err := writeJSONAs(w, statusCode, JSONAPIContentType, doc)
+	return err
+}
@@ -0,0 +1,58 @@
+package respondwithjson
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware recupera los panics de los handlers y responde 500 con una JsonResponse en lugar del HTML por defecto.
+// El panic y la traza se envían a ErrorLogger; el cliente sólo recibe un error genérico.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler se usa para abortar la respuesta a propósito y no se debe capturar
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			logError(http.StatusInternalServerError, fmt.Errorf("panic: %v\n%s", rec, debug.Stack()))
+
+			response := NewJsonResponseWithStatus(StatusError, localizedMessage(MessageKeyError, ""), nil, "internal server error")
+			RespondWithJSON(w, http.StatusInternalServerError, response)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Handler adapta un handler que devuelve error a http.HandlerFunc.
+// Si fn devuelve un error se responde con RespondWithMappedError (HTTPError, errores registrados o 500).
+// Ejemplo: mux.Handle("/users", Handler(func(w http.ResponseWriter, r *http.Request) error { ... }))
+func Handler(fn func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			RespondWithMappedError(w, err)
+		}
+	}
+}
+
+// RequireJSONContentType responde 415 con una JsonResponse a las peticiones POST, PUT y PATCH con cuerpo
+// cuyo Content-Type no es application/json ni un tipo +json como application/merge-patch+json
+// (los parámetros como charset se ignoran).
+// El resto de métodos (GET, DELETE...) y las peticiones sin cuerpo pasan sin comprobarse.
+func RequireJSONContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			hasBody := r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+			if hasBody && !isJSONContentType(r.Header.Get("Content-Type")) {
+				RespondWithError(w, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
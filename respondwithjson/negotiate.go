@@ -0,0 +1,95 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Responder en XML o JSON según la cabecera Accept de la petición.
+// Se responde en XML (application/xml) sólo cuando el cliente lo prefiere; con Accept ausente o */* se responde en JSON,
+// con sangría si la petición lleva ?pretty=true. Si Data tiene un tipo que XML no admite (ej. un mapa)
+// se responde en JSON aunque el cliente prefiera XML, en lugar de fallar con un 500.
+func Respond(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	w.Header().Add("Vary", "Accept")
+	if prefersXML(r) {
+		buf, err := encodeXML(prepareResponse(response))
+		var unsupported *xml.UnsupportedTypeError
+		if !errors.As(err, &unsupported) {
+			return writeXML(w, statusCode, buf, err)
+		}
+	}
+	return RespondWithJSONPretty(w, r, statusCode, response)
+}
+
+// encodeXML codifica el valor en XML (con la cabecera <?xml ...?>) en un buffer del pool
+func encodeXML(v interface{}) (*bytes.Buffer, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(buf).Encode(v); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// writeXML escribe las cabeceras, el código de estado y el cuerpo codificado por encodeXML, o un 500 si falló
+func writeXML(w http.ResponseWriter, statusCode int, buf *bytes.Buffer, err error) error {
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer putBuffer(buf)
+	_, err = writeBody(w, statusCode, "application/xml", buf.Bytes())
+	return err
+}
+
+// MarshalXML codifica la respuesta en XML omitiendo el elemento <errors> cuando no hay errores
+// (con la etiqueta errors>error encoding/xml escribe siempre el elemento padre, aunque esté vacío)
+func (r JsonResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// Con MarshalXML encoding/xml propone el nombre del tipo; se mantiene <response> como con la etiqueta de XMLName
+	if r.XMLName.Local != "" {
+		start.Name = r.XMLName
+	} else if start.Name.Local == "JsonResponse" {
+		start.Name = xml.Name{Local: "response"}
+	}
+	out := xmlResponse{jsonResponseAlias: jsonResponseAlias(r)}
+	if len(r.Errors) > 0 {
+		out.Errors = &xmlErrors{Error: r.Errors}
+	}
+	return e.EncodeElement(out, start)
+}
+
+// xmlResponse es JsonResponse con Errors como puntero para que omitempty omita el elemento <errors>
+type xmlResponse struct {
+	jsonResponseAlias
+	Errors *xmlErrors `xml:"errors,omitempty"`
+}
+
+// xmlErrors es la lista de errores en XML: <errors><error>...</error></errors>
+type xmlErrors struct {
+	Error []string `xml:"error"`
+}
+
+// prefersXML indica si el cliente prefiere XML frente a JSON según el peso q de la cabecera Accept.
+// Un tipo XML explícito gana a los comodines (*/*, application/*) con el mismo peso.
+func prefersXML(r *http.Request) bool {
+	var jsonQ, xmlQ, wildcardQ float64
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := qValue(params)
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "application/xml", "text/xml":
+			xmlQ = max(xmlQ, q)
+		case "application/*", "*/*":
+			wildcardQ = max(wildcardQ, q)
+		}
+	}
+	return xmlQ > jsonQ && xmlQ >= wildcardQ
+}
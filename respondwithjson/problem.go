@@ -0,0 +1,61 @@
+package respondwithjson
+
+import "net/http"
+
+// ProblemContentType es el tipo de contenido de las respuestas de error RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Problem es un objeto Problem Details (RFC 7807).
+// Extensions se añade al objeto de nivel superior; sus claves no pueden sobrescribir los miembros estándar.
+type Problem struct {
+	Type       string                 `json:"type,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Status     int                    `json:"status,omitempty"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON codifica los miembros estándar junto con las extensiones en el mismo objeto
+func (p Problem) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		out[key] = value
+	}
+	// Los miembros estándar tienen prioridad sobre las extensiones con el mismo nombre
+	for _, key := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(out, key)
+	}
+	if p.Type != "" {
+		out["type"] = p.Type
+	}
+	if p.Title != "" {
+		out["title"] = p.Title
+	}
+	if p.Status != 0 {
+		out["status"] = p.Status
+	}
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+	return Marshal(out)
+}
+
+// Función para enviar un error en formato RFC 7807 (application/problem+json) usando p.Status como código de estado.
+// Si Status es 0 se usa 500; si Type está vacío se usa "about:blank" y, en ese caso, Title por defecto es el texto del código.
+func RespondWithProblem(w http.ResponseWriter, p Problem) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" && p.Type == "about:blank" {
+		p.Title = http.StatusText(p.Status)
+	}
+	_, err := writeJSONAs(w, p.Status, ProblemContentType, p)
+	return err
+}
@@ -0,0 +1,120 @@
+package respondwithjson
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidQueryParam se devuelve cuando un parámetro de la query no se puede convertir al tipo del campo (responder con 400)
+type ErrInvalidQueryParam struct {
+	Param    string
+	Expected string
+}
+
+// Error devuelve un mensaje que se puede mostrar al cliente
+func (e ErrInvalidQueryParam) Error() string {
+	return fmt.Sprintf("query parameter '%s' must be %s", e.Param, e.Expected)
+}
+
+// BindQuery rellena los campos de la estructura con la etiqueta `query:"nombre"` a partir de r.URL.Query()
+// y después aplica Validate. Admite string, bool, enteros, floats, punteros a ellos y slices (parámetros repetidos,
+// ej. ?tag=a&tag=b). Los parámetros ausentes dejan el campo como estaba y los que no se pueden convertir
+// devuelven un ErrInvalidQueryParam con el nombre del parámetro.
+// Ejemplo: Page int `query:"page" validate:"min=1"`
+func BindQuery(r *http.Request, object interface{}) error {
+	val := reflect.ValueOf(object)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return errors.New("object must be a non-nil pointer to a struct")
+	}
+	if err := bindQueryStruct(val.Elem(), r.URL.Query()); err != nil {
+		return err
+	}
+	return Validate(object)
+}
+
+// bindQueryStruct asigna los parámetros a los campos con etiqueta query, recorriendo las estructuras embebidas
+func bindQueryStruct(val reflect.Value, query map[string][]string) error {
+	typeOfS := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typeOfS.Field(i)
+		value := val.Field(i)
+
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			if err := bindQueryStruct(value, query); err != nil {
+				return err
+			}
+			continue
+		}
+		name := strings.TrimSpace(strings.Split(field.Tag.Get("query"), ",")[0])
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		params, ok := query[name]
+		if !ok || len(params) == 0 {
+			continue
+		}
+		if err := setQueryValue(value, name, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setQueryValue convierte los valores del parámetro al tipo del campo
+func setQueryValue(value reflect.Value, name string, params []string) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(value.Type().Elem())
+		if err := setQueryValue(elem.Elem(), name, params); err != nil {
+			return err
+		}
+		value.Set(elem)
+		return nil
+	case reflect.Slice:
+		slice := reflect.MakeSlice(value.Type(), len(params), len(params))
+		for i, param := range params {
+			if err := setQueryValue(slice.Index(i), name, []string{param}); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+
+	param := params[0]
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(param)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return ErrInvalidQueryParam{Param: name, Expected: "a boolean"}
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(param, 10, value.Type().Bits())
+		if err != nil {
+			return ErrInvalidQueryParam{Param: name, Expected: "an integer"}
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param, 10, value.Type().Bits())
+		if err != nil {
+			return ErrInvalidQueryParam{Param: name, Expected: "a non-negative integer"}
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(param, value.Type().Bits())
+		if err != nil {
+			return ErrInvalidQueryParam{Param: name, Expected: "a number"}
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: unsupported type %s for query parameter '%s'", ErrInvalidRule, value.Type(), name)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package respondwithjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue es el valor que sustituye a los campos sensibles
+const RedactedValue = "***"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Esta función convierte un objeto a JSON sustituyendo por "***" el valor de los campos sensibles.
+// Un campo es sensible si su etiqueta json incluye la opción redact (`json:"password,redact"`)
+// o si tiene la etiqueta `sensitive:"true"`. Recorre estructuras anidadas, punteros, slices y mapas.
+func ConvertObjectToJSONRedacted(obj interface{}) (string, error) {
+	jsonData, err := Marshal(redactValue(reflect.ValueOf(obj)))
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// redactValue devuelve una copia del valor, apta para json.Marshal, con los campos sensibles sustituidos
+func redactValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	// Los tipos con su propio marshaler (ej. time.Time) se codifican tal cual
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil
+		}
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		redactStruct(v, out)
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// redactStruct copia en out los campos exportados de la estructura usando sus nombres JSON
+func redactStruct(v reflect.Value, out map[string]interface{}) {
+	typeOfS := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := typeOfS.Field(i)
+		value := v.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		// Los campos de las estructuras embebidas sin nombre JSON se promocionan al nivel superior
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				redactStruct(embedded, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if hasTagOption(opts, "omitempty") && isEmptyJSONValue(value) {
+			continue
+		}
+		if hasTagOption(opts, "redact") || field.Tag.Get("sensitive") == "true" {
+			out[name] = RedactedValue
+			continue
+		}
+		out[name] = redactValue(value)
+	}
+}
+
+// hasTagOption indica si la lista de opciones de una etiqueta (separadas por comas) contiene la opción
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyJSONValue reproduce el criterio de omitempty de encoding/json
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+// MaskFields devuelve el objeto en JSON con los campos indicados sustituidos por "***", sin depender de las etiquetas
+// y sin modificar el objeto original. Los campos son nombres JSON y admiten rutas con puntos para objetos anidados
+// (ej. "user.password"); si un nivel de la ruta es un array se aplica a cada elemento.
+func MaskFields(obj interface{}, fields ...string) (string, error) {
+	tree, err := objectToMap(obj)
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
+		maskPath(tree, strings.Split(field, "."))
+	}
+	return ConvertObjectToJSON(tree)
+}
+
+// maskPath sustituye el valor al final de la ruta dentro del árbol JSON decodificado
+func maskPath(node interface{}, path []string) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		value, ok := n[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			n[path[0]] = RedactedValue
+			return
+		}
+		maskPath(value, path[1:])
+	case []interface{}:
+		for _, item := range n {
+			maskPath(item, path)
+		}
+	}
+}
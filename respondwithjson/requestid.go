@@ -0,0 +1,34 @@
+package respondwithjson
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader es la cabecera con el identificador de la petición (correlation ID)
+const RequestIDHeader = "X-Request-ID"
+
+// Responder con el formato JSON incluyendo el identificador de la petición.
+// Lee la cabecera X-Request-ID de la petición (o genera un UUID si no existe) y la devuelve
+// tanto en la cabecera de la respuesta como en el campo request_id del JSON.
+func RespondWithJSONContext(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = newUUID()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+	response.RequestID = requestID
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// newUUID genera un UUID versión 4 aleatorio
+func newUUID() string {
+	var b [16]byte
+	// crypto/rand.Read no devuelve error en las plataformas soportadas
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -1,23 +1,159 @@
 package respondwithjson
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// JsonResponse es la estructura de la respuesta en formato JSON
+// JsonResponse es la estructura de la respuesta en formato JSON (y XML, ver Respond, y YAML, ver el paquete respondwithyaml)
 type JsonResponse struct {
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	XMLName   xml.Name    `json:"-" xml:"response" yaml:"-"`
+	Status    string      `json:"status,omitempty" xml:"status,omitempty" yaml:"status,omitempty"`
+	Message   string      `json:"message,omitempty" xml:"message,omitempty" yaml:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty" xml:"data,omitempty" yaml:"data,omitempty"`
+	Error     string      `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+	Code      string      `json:"code,omitempty" xml:"code,omitempty" yaml:"code,omitempty"`
+	RequestID string      `json:"request_id,omitempty" xml:"request_id,omitempty" yaml:"request_id,omitempty"`
+	Timestamp string      `json:"timestamp,omitempty" xml:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Errors    []string    `json:"errors,omitempty" xml:"errors>error,omitempty" yaml:"errors,omitempty"`
+
+	// Los mapas no se pueden codificar en XML, por eso se omiten en ese formato
+	Details    map[string]string `json:"details,omitempty" xml:"-" yaml:"details,omitempty"`
+	Links      map[string]string `json:"_links,omitempty" xml:"-" yaml:"_links,omitempty"`
+	Pagination *Pagination       `json:"pagination,omitempty" xml:"pagination,omitempty" yaml:"pagination,omitempty"`
+
+	// Meta lleva metadatos libres (ej. "total", "version") bajo la clave "meta", por lo que nunca
+	// pueden sobrescribir las claves reservadas del sobre (status, message, data, error...)
+	Meta map[string]interface{} `json:"meta,omitempty" xml:"-" yaml:"meta,omitempty"`
+}
+
+// MarshalJSON normaliza el campo Data para que los clientes reciban siempre la misma forma:
+// un slice vacío o nil se codifica siempre como "data":[] (nunca se omite), mientras que
+// un mapa nil, un puntero nil con tipo o un Data nil se omiten. La excepción es un []byte nil, que se codifica
+// como null porque [] no es una cadena base64 válida. El resto de valores se codifican sin cambios.
+func (r JsonResponse) MarshalJSON() ([]byte, error) {
+	return Marshal(r.normalized())
+}
+
+// jsonResponseAlias no tiene el método MarshalJSON, así se evita la recursión
+type jsonResponseAlias JsonResponse
+
+// normalized devuelve la respuesta con Data normalizado, lista para codificarla directamente con un Encoder.
+// encodeJSON la usa para escribir en el buffer del pool sin el buffer intermedio de MarshalJSON.
+func (r JsonResponse) normalized() *jsonResponseAlias {
+	alias := jsonResponseAlias(r)
+	alias.Data = normalizeData(r.Data)
+	return &alias
+}
+
+// normalizeData aplica a Data la convención de MarshalJSON
+func normalizeData(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	if isNilPointer(data) {
+		return nil
+	}
+	v := reflect.ValueOf(data)
+	if v.Type().Implements(jsonMarshalerType) {
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+			return nil
+		}
+		return data
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8 {
+			return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return data
+}
+
+// isNilPointer indica si el valor es un puntero nil con tipo (ej. (*User)(nil)), que omitempty no omite
+// porque la interfaz que lo contiene no es nil
+func isNilPointer(data interface{}) bool {
+	v := reflect.ValueOf(data)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// Pagination contiene los metadatos de paginación de una respuesta
+type Pagination struct {
+	Page       int `json:"page" xml:"page" yaml:"page"`
+	PageSize   int `json:"page_size" xml:"page_size" yaml:"page_size"`
+	TotalItems int `json:"total_items" xml:"total_items" yaml:"total_items"`
+	TotalPages int `json:"total_pages" xml:"total_pages" yaml:"total_pages"`
+}
+
+// Mensajes estándar de los helpers. Se pueden cambiar una vez al arrancar (ej. "ok" y "failed")
+var (
+	SuccessMessage = "Success"
+	ErrorMessage   = "ERROR"
+)
+
+// ErrorLogger, si no es nil, se invoca en cada respuesta de error con el código de estado y el error.
+// Permite registrar los errores sin añadir una dependencia de logging al paquete.
+var ErrorLogger func(statusCode int, err error)
+
+// logError invoca ErrorLogger si está configurado y hay un error
+func logError(statusCode int, err error) {
+	if ErrorLogger != nil && err != nil {
+		ErrorLogger(statusCode, err)
+	}
 }
 
+// ResponseObserver, si no es nil, se invoca con el código de estado de cada respuesta justo después de enviarlo.
+// Sirve para métricas (ej. un contador de Prometheus por clase de estado); por defecto no tiene ningún coste.
+var ResponseObserver func(statusCode int)
+
+// writeHeader envía el código de estado y lo notifica a ResponseObserver
+func writeHeader(w http.ResponseWriter, statusCode int) {
+	w.WriteHeader(statusCode)
+	if ResponseObserver != nil {
+		ResponseObserver(statusCode)
+	}
+}
+
+// IncludeTimestamp hace que todas las respuestas incluyan la hora UTC del servidor (RFC3339) en el campo timestamp
+var IncludeTimestamp bool
+
+// PrettyPrint hace que las respuestas JSON se codifiquen con sangría de dos espacios (útil en desarrollo).
+// Desactivado por defecto; para activarlo sólo en algunas peticiones usar ?pretty=true con RespondWithJSONPretty.
+var PrettyPrint bool
+
+// Now es la fuente de la hora actual del campo timestamp y de las cabeceras de caché; se puede sustituir en los
+// tests para obtener resultados deterministas. La usan también los paquetes hermanos (ej. respondwithyaml).
+var Now = time.Now
+
+// Valores del campo Status que usan los helpers
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
 // Constructor para la respuesta JsonResponse
+// Un puntero nil con tipo en data se guarda como nil para que el campo se omita en todos los formatos.
 func NewJsonResponse(message string, data interface{}, err string) JsonResponse {
+	if isNilPointer(data) {
+		data = nil
+	}
 	return JsonResponse{
 		Message: message,
 		Data:    data,
@@ -25,11 +161,368 @@ func NewJsonResponse(message string, data interface{}, err string) JsonResponse
 	}
 }
 
-// Responder con el formato JSON
-func RespondWithJSON(w http.ResponseWriter, statusCode int, response JsonResponse) {
+// Constructor para la respuesta JsonResponse con el campo Status explícito
+func NewJsonResponseWithStatus(status, message string, data interface{}, err string) JsonResponse {
+	response := NewJsonResponse(message, data, err)
+	response.Status = status
+	return response
+}
+
+// WithMessage devuelve una copia de la respuesta con el mensaje indicado
+func (r JsonResponse) WithMessage(message string) JsonResponse {
+	r.Message = message
+	return r
+}
+
+// WithData devuelve una copia de la respuesta con los datos indicados
+func (r JsonResponse) WithData(data interface{}) JsonResponse {
+	r.Data = data
+	return r
+}
+
+// WithError devuelve una copia de la respuesta con el error indicado
+func (r JsonResponse) WithError(err string) JsonResponse {
+	r.Error = err
+	return r
+}
+
+// WithMeta devuelve una copia de la respuesta con la clave añadida a Meta; no modifica el mapa Meta original.
+// Ejemplo: NewJsonResponse("", users, "").WithMeta("total", n).Send(w, http.StatusOK)
+func (r JsonResponse) WithMeta(key string, value interface{}) JsonResponse {
+	meta := make(map[string]interface{}, len(r.Meta)+1)
+	for k, v := range r.Meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	r.Meta = meta
+	return r
+}
+
+// Send envía la respuesta con RespondWithJSON. El error es sólo para logging
+func (r JsonResponse) Send(w http.ResponseWriter, statusCode int) error {
+	_, err := RespondWithJSON(w, statusCode, r)
+	return err
+}
+
+// Responder con el formato JSON. Devuelve el número de bytes del cuerpo escritos (útil para los logs de acceso).
+// La respuesta se codifica antes de enviar el código de estado: si la codificación falla se responde 500
+// con un error genérico y se devuelve el error de codificación para registrarlo (logging).
+// Con el encoder por defecto <, > y & se escapan en los strings (ver RespondWithJSONEscaped).
+func RespondWithJSON(w http.ResponseWriter, statusCode int, response JsonResponse) (int, error) {
+	return writeJSON(w, statusCode, prepareResponse(response))
+}
+
+// RespondWithJSONErr es igual que RespondWithJSON para quien no necesita el número de bytes: sólo devuelve el error.
+// El error es sólo para logging: la respuesta ya se ha enviado cuando se devuelve.
+func RespondWithJSONErr(w http.ResponseWriter, statusCode int, response JsonResponse) error {
+	_, err := RespondWithJSON(w, statusCode, response)
+	return err
+}
+
+// RespondWithJSONVoid es el envoltorio sin valor de retorno de RespondWithJSON, con la firma original,
+// para quien no necesita ni el número de bytes ni el error (los fallos de codificación ya responden 500).
+func RespondWithJSONVoid(w http.ResponseWriter, statusCode int, response JsonResponse) {
+	RespondWithJSON(w, statusCode, response)
+}
+
+// Igual que RespondWithJSON pero añade antes las cabeceras indicadas (ej. Location, X-RateLimit-Remaining).
+// Las cabeceras se aplican antes de WriteHeader, ya que después de enviar el código de estado se ignoran.
+// Content-Type y Content-Length los fija siempre la propia respuesta.
+func RespondWithJSONHeaders(w http.ResponseWriter, statusCode int, headers map[string]string, response JsonResponse) error {
+	for key, value := range headers {
+		w.Header().Set(key, value)
+	}
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// Igual que RespondWithJSON pero garantiza que <, > y & (y U+2028, U+2029) se escapan en los strings
+// (\u003c, \u003e, \u0026) aplicando json.HTMLEscape al resultado, aunque se hayan sustituido Marshal o NewEncoder
+// por una implementación que no lo haga. Con el encoder por defecto (encoding/json) RespondWithJSON ya los escapa.
+func RespondWithJSONEscaped(w http.ResponseWriter, statusCode int, response JsonResponse) error {
+	buf, err := encodeJSON(prepareResponse(response))
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer putBuffer(buf)
+
+	escaped := bufferPool.Get().(*bytes.Buffer)
+	escaped.Reset()
+	defer putBuffer(escaped)
+	json.HTMLEscape(escaped, buf.Bytes())
+
+	_, err = writeBody(w, statusCode, "application/json", escaped.Bytes())
+	return err
+}
+
+// Igual que RespondWithJSON pero con sangría si PrettyPrint está activado o la petición lleva ?pretty=true
+// (también ?pretty o ?pretty=1). Ejemplo: GET /users?pretty=true desde el navegador.
+func RespondWithJSONPretty(w http.ResponseWriter, r *http.Request, statusCode int, response JsonResponse) error {
+	w = skipBodyForHead(w, r)
+	buf, err := encodeJSONIndent(prepareResponse(response), PrettyPrint || prettyRequested(r))
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer putBuffer(buf)
+	_, err = writeBody(w, statusCode, "application/json", buf.Bytes())
+	return err
+}
+
+// prettyRequested indica si la query de la petición pide la respuesta con sangría (?pretty, ?pretty=true, ?pretty=1)
+func prettyRequested(r *http.Request) bool {
+	if r == nil || r.URL == nil {
+		return false
+	}
+	values, ok := r.URL.Query()["pretty"]
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(values[0]) {
+	case "", "1", "true":
+		return true
+	}
+	return false
+}
+
+// Igual que RespondWithJSON pero no escribe nada si el contexto (ej. r.Context()) ya se ha cancelado.
+// Se comprueba antes y después de codificar la respuesta; en ese caso devuelve ctx.Err().
+func RespondWithJSONCtx(ctx context.Context, w http.ResponseWriter, statusCode int, response JsonResponse) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	buf, err := encodeJSON(prepareResponse(response))
+	if err != nil {
+		writeEncodeFailure(w)
+		return err
+	}
+	defer putBuffer(buf)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err = writeBody(w, statusCode, "application/json", buf.Bytes())
+	return err
+}
+
+// prepareResponse completa la respuesta con los campos automáticos (timestamp) antes de codificarla
+func prepareResponse(response JsonResponse) JsonResponse {
+	response.Timestamp = stampTimestamp(response.Timestamp)
+	return response
+}
+
+// stampTimestamp devuelve la hora actual en RFC3339 si IncludeTimestamp está activo y el timestamp está vacío
+func stampTimestamp(timestamp string) string {
+	if IncludeTimestamp && timestamp == "" {
+		return Now().UTC().Format(time.RFC3339)
+	}
+	return timestamp
+}
+
+// encodeFailureBody devuelve la respuesta que se envía cuando no se puede codificar la respuesta original,
+// con el mensaje de error de Messages. No usa Marshal (la variable del paquete) porque puede ser la que ha fallado.
+func encodeFailureBody() string {
+	message, err := json.Marshal(localizedMessage(MessageKeyError, ""))
+	if err != nil {
+		message = []byte(`"ERROR"`)
+	}
+	return `{"status":"error","message":` + string(message) + `,"error":"internal server error"}` + "\n"
+}
+
+// bufferPool reutiliza los buffers en los que se codifican las respuestas
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// maxPooledBufferSize evita que los buffers de respuestas muy grandes se queden retenidos en el pool
+const maxPooledBufferSize = 64 << 10
+
+// pooledEncoder es un json.Encoder reutilizable; out permite cambiar el buffer en el que escribe
+type pooledEncoder struct {
+	out targetWriter
+	enc *json.Encoder
+}
+
+// targetWriter redirige las escrituras al writer actual
+type targetWriter struct {
+	w io.Writer
+}
+
+func (t *targetWriter) Write(p []byte) (int, error) {
+	return t.w.Write(p)
+}
+
+// encoderPool reutiliza los encoders de json.NewEncoder para no crear uno nuevo en cada respuesta
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		return &pooledEncoder{}
+	},
+}
+
+// encodeJSON codifica el valor en un buffer del pool con un encoder del pool; hay que devolver el buffer con putBuffer.
+// Usa sangría si PrettyPrint está activado.
+func encodeJSON(v interface{}) (*bytes.Buffer, error) {
+	return encodeJSONIndent(v, PrettyPrint)
+}
+
+// encodeJSONIndent es igual que encodeJSON pero indicando si se usa sangría de dos espacios
+func encodeJSONIndent(v interface{}, pretty bool) (*bytes.Buffer, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	if response, ok := v.(JsonResponse); ok {
+		v = response.normalized()
+	}
+
+	var enc Encoder
+	var pooled *pooledEncoder
+	if usesDefaultEncoder() {
+		pooled = encoderPool.Get().(*pooledEncoder)
+		if pooled.enc == nil {
+			pooled.enc = json.NewEncoder(&pooled.out)
+		}
+		pooled.out.w = buf
+		enc = pooled.enc
+	} else {
+		enc = NewEncoder(buf)
+	}
+	// Los encoders del pool se comparten, así que la sangría se fija en cada uso.
+	// El escape de HTML (<, >, & como \u003c...) es el valor por defecto de encoding/json; se fija explícitamente
+	// por si NewEncoder crea encoders con otro valor por defecto.
+	if escaper, ok := enc.(htmlEscapeSetter); ok {
+		escaper.SetEscapeHTML(true)
+	}
+	setter, canIndent := enc.(indentSetter)
+	if canIndent {
+		if pretty {
+			setter.SetIndent("", "  ")
+		} else {
+			setter.SetIndent("", "")
+		}
+	}
+	err := enc.Encode(v)
+	if pooled != nil {
+		pooled.out.w = nil
+		encoderPool.Put(pooled)
+	}
+
+	if err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	if pretty && !canIndent {
+		// El Encoder no admite sangría: se aplica sobre el resultado
+		indented := bufferPool.Get().(*bytes.Buffer)
+		indented.Reset()
+		err := json.Indent(indented, buf.Bytes(), "", "  ")
+		putBuffer(buf)
+		if err != nil {
+			putBuffer(indented)
+			return nil, err
+		}
+		return indented, nil
+	}
+	return buf, nil
+}
+
+// putBuffer devuelve el buffer al pool
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// writeJSON codifica el valor en un buffer y después escribe las cabeceras, el código de estado y el cuerpo.
+// Devuelve el número de bytes del cuerpo escritos.
+// Si la codificación falla responde 500 con un error genérico y devuelve el error de codificación.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) (int, error) {
+	return writeJSONAs(w, statusCode, "application/json", v)
+}
+
+// writeJSONAs es igual que writeJSON pero con otro Content-Type JSON (ej. application/problem+json)
+func writeJSONAs(w http.ResponseWriter, statusCode int, contentType string, v interface{}) (int, error) {
+	buf, err := encodeJSON(v)
+	if err != nil {
+		writeEncodeFailure(w)
+		return 0, err
+	}
+	defer putBuffer(buf)
+	return writeBody(w, statusCode, contentType, buf.Bytes())
+}
+
+// writeBody escribe el Content-Type, el Content-Length, el código de estado y el cuerpo ya codificado.
+// Devuelve el número de bytes del cuerpo escritos.
+func writeBody(w http.ResponseWriter, statusCode int, contentType string, body []byte) (int, error) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	writeHeader(w, statusCode)
+	return w.Write(body)
+}
+
+// headResponseWriter descarta el cuerpo de la respuesta: HEAD debe enviar las mismas cabeceras que GET
+// (incluido Content-Length, que se calcula con el cuerpo ya codificado) pero sin cuerpo
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write descarta los datos e indica que se han escrito todos
+func (h headResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Unwrap devuelve el writer envuelto para http.ResponseController
+func (h headResponseWriter) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+// skipBodyForHead devuelve un writer que omite el cuerpo si la petición es HEAD
+func skipBodyForHead(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if r != nil && r.Method == http.MethodHead {
+		return headResponseWriter{w}
+	}
+	return w
+}
+
+// writeEncodeFailure responde 500 con el cuerpo genérico de error
+func writeEncodeFailure(w http.ResponseWriter) {
+	body := encodeFailureBody()
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Del("Content-Encoding")
+	writeHeader(w, http.StatusInternalServerError)
+	io.WriteString(w, body)
+}
+
+// Responder con bytes JSON ya codificados (ej. guardados en caché), sin volver a codificarlos.
+// Si rawJSON no es un JSON válido responde 500 y devuelve el error.
+func RespondWithRaw(w http.ResponseWriter, statusCode int, rawJSON json.RawMessage) error {
+	if !json.Valid(rawJSON) {
+		writeEncodeFailure(w)
+		return errors.New("raw message is not valid JSON")
+	}
+	_, err := writeBody(w, statusCode, "application/json", rawJSON)
+	return err
+}
+
+// Responder con el envoltorio JSON usando bytes ya codificados como campo data, sin decodificarlos
+func RespondWithRawData(w http.ResponseWriter, statusCode int, rawJSON json.RawMessage) error {
+	response := NewJsonResponse("", rawJSON, "")
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// Igual que RespondWithValidationError pero a partir de los ValidationError (ej. FieldErrors(Validate(obj))).
+// Si un campo tiene varios fallos se usa el primero.
+func RespondWithValidationErrors(w http.ResponseWriter, errs []ValidationError) {
+	fieldErrors := make(map[string]string, len(errs))
+	for _, e := range errs {
+		if _, exists := fieldErrors[e.Field]; !exists {
+			fieldErrors[e.Field] = e.Message
+		}
+	}
+	RespondWithValidationError(w, fieldErrors)
 }
 
 // Responder con JSON simple (simplemente data)
@@ -38,109 +531,453 @@ func RespondWithJSONSimple(w http.ResponseWriter, statusCode int, data interface
 	RespondWithJSON(w, statusCode, response)
 }
 
+// Responder sólo con un mensaje, sin datos (ej. "Updated successfully")
+func RespondWithMessage(w http.ResponseWriter, statusCode int, message string) {
+	response := NewJsonResponse(message, nil, "")
+	RespondWithJSON(w, statusCode, response)
+}
+
+// Responder con el objeto codificado directamente en el nivel superior, sin el envoltorio message/data/error.
+// Útil para clientes que esperan el objeto tal cual (ej. SDKs de terceros). El error es sólo para logging.
+func RespondWithBare(w http.ResponseWriter, statusCode int, obj interface{}) error {
+	_, err := writeJSON(w, statusCode, obj)
+	return err
+}
+
 // Función para enviar una respuesta exitosa
 func RespondWithSuccess(w http.ResponseWriter, data interface{}) {
-	response := NewJsonResponse("Success", data, "")
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, ""), data, "")
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// Función para enviar una respuesta de recurso creado (201)
+func RespondWithCreated(w http.ResponseWriter, data interface{}) {
+	response := NewJsonResponseWithStatus(StatusSuccess, "Created", data, "")
+	RespondWithJSON(w, http.StatusCreated, response)
+}
+
+// Función para enviar una respuesta de petición aceptada (202)
+func RespondWithAccepted(w http.ResponseWriter, data interface{}) {
+	response := NewJsonResponseWithStatus(StatusSuccess, "Accepted", data, "")
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// JobAccepted es la respuesta de un trabajo asíncrono aceptado: su identificador y la URL para consultar su estado
+type JobAccepted struct {
+	JobID     string `json:"job_id" xml:"job_id" yaml:"job_id"`
+	StatusURL string `json:"status_url,omitempty" xml:"status_url,omitempty" yaml:"status_url,omitempty"`
+}
+
+// Función para enviar la aceptación (202) de un trabajo asíncrono con la cabecera Location apuntando a StatusURL
+func RespondWithJobAccepted(w http.ResponseWriter, job JobAccepted) {
+	if job.StatusURL != "" {
+		w.Header().Set("Location", job.StatusURL)
+	}
+	RespondWithAccepted(w, job)
+}
+
+// Función para enviar una redirección (3xx) con la cabecera Location y la URL también en el cuerpo
+// ({"data":{"location":"..."}}), para los clientes que no siguen las redirecciones automáticamente (ej. fetch).
+// Si statusCode no es una redirección (o es 304, que no admite cuerpo) devuelve un error sin escribir nada.
+func RespondWithRedirect(w http.ResponseWriter, statusCode int, location string) error {
+	if statusCode < 300 || statusCode > 399 || statusCode == http.StatusNotModified {
+		return fmt.Errorf("invalid redirect status code: %d", statusCode)
+	}
+	w.Header().Set("Location", location)
+	response := NewJsonResponse("", map[string]string{"location": location}, "")
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// Función para enviar una respuesta sin contenido (204). No escribe cuerpo ni Content-Type
+func RespondWithNoContent(w http.ResponseWriter) {
+	writeHeader(w, http.StatusNoContent)
+}
+
+// Función para enviar una respuesta exitosa con enlaces a recursos relacionados (HATEOAS).
+// Las claves son los nombres de la relación (self, next, prev) y los valores las URLs absolutas o relativas.
+func RespondWithLinks(w http.ResponseWriter, data interface{}, links map[string]string) {
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, ""), data, "")
+	response.Links = links
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// Función para enviar una respuesta exitosa con metadatos adicionales en la clave "meta" (ej. {"total": 42}).
+// Si algún valor de meta no se puede codificar en JSON se responde con un error 500 genérico.
+func RespondWithMeta(w http.ResponseWriter, data interface{}, meta map[string]interface{}) {
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, ""), data, "")
+	response.Meta = meta
 	RespondWithJSON(w, http.StatusOK, response)
 }
 
-// Función para enviar una respuesta con el error
+// Función para enviar una lista (slice o array) con el número de elementos en meta.count.
+// Si items no es un slice o array devuelve un error sin escribir nada, para que el llamador decida la respuesta.
+func RespondWithCollection(w http.ResponseWriter, items interface{}) error {
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("items must be a slice or array, got %s", v.Kind())
+	}
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, ""), v.Interface(), "")
+	response.Meta = map[string]interface{}{"count": v.Len()}
+	return RespondWithJSONErr(w, http.StatusOK, response)
+}
+
+// Función para enviar una respuesta paginada. Si TotalPages es 0 se calcula a partir de TotalItems y PageSize
+func RespondWithPaginated(w http.ResponseWriter, data interface{}, p Pagination) {
+	if p.TotalPages == 0 && p.PageSize > 0 {
+		p.TotalPages = (p.TotalItems + p.PageSize - 1) / p.PageSize
+	}
+	response := NewJsonResponseWithStatus(StatusSuccess, localizedMessage(MessageKeySuccess, ""), data, "")
+	response.Pagination = &p
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// Función para enviar una respuesta con el error.
+// Si err es (o envuelve) un HTTPError se usan su código de error y, si lo tiene, su código de estado en lugar de statusCode.
 func RespondWithError(w http.ResponseWriter, statusCode int, err error) {
+	respondWithError(w, statusCode, err, "")
+}
+
+// respondWithError envía la respuesta de error con el mensaje en el idioma lang
+func respondWithError(w http.ResponseWriter, statusCode int, err error, lang string) {
+	if httpErr, ok := asHTTPError(err); ok {
+		respondWithHTTPError(w, httpErr, statusCode, lang)
+		return
+	}
+	logError(statusCode, err)
 	var errMsg, message string
 	if err != nil {
 		errMsg = err.Error()
-		message = "ERROR"
+		message = localizedMessage(MessageKeyError, lang)
 	}
-	response := NewJsonResponse(message, nil, errMsg)
+	response := NewJsonResponseWithStatus(StatusError, message, nil, errMsg)
 	RespondWithJSON(w, statusCode, response)
 }
 
-// Responder con JSON simple (simplemente data)
-func RespondWithJSONMessageError(w http.ResponseWriter, statusCode int, messageError string) {
-	response := NewJsonResponse("", "", messageError)
-	RespondWithJSON(w, statusCode, response)
-}
+// Función para enviar una respuesta con varios errores (ej. operaciones por lotes). Los errores nil se ignoran.
+// Si sólo hay un error se usa el campo error, igual que RespondWithError; si hay varios, el campo errors.
+func RespondWithErrors(w http.ResponseWriter, statusCode int, errs []error) {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		RespondWithError(w, statusCode, nil)
+		return
+	case 1:
+		RespondWithError(w, statusCode, nonNil[0])
+		return
+	}
 
-// Verificar y responder con JSON correcto
-func CheckAndRespondJSON(w http.ResponseWriter, r *http.Request, object interface{}) error {
-	if r.Body == nil {
-		err := errors.New("request body is empty")
-		return err
+	logError(statusCode, errors.Join(nonNil...))
+	response := NewJsonResponseWithStatus(StatusError, localizedMessage(MessageKeyError, ""), nil, "")
+	for _, err := range nonNil {
+		response.Errors = append(response.Errors, err.Error())
 	}
+	RespondWithJSON(w, statusCode, response)
+}
 
-	decoder := json.NewDecoder(r.Body)
-	decoder.DisallowUnknownFields() // Evita la decodificación si JSON contiene campos que no están en la estructura
-	err := decoder.Decode(object)
+// Función para enviar una respuesta con el error y un código de error estable para el cliente (ej. USER_NOT_FOUND)
+func RespondWithErrorCode(w http.ResponseWriter, statusCode int, code string, err error) {
+	logError(statusCode, err)
+	var errMsg, message string
 	if err != nil {
-		return err
+		errMsg = err.Error()
+		message = localizedMessage(MessageKeyError, "")
 	}
-	return nil
+	response := NewJsonResponseWithStatus(StatusError, message, nil, errMsg)
+	response.Code = code
+	RespondWithJSON(w, statusCode, response)
 }
 
-// Esta función obtiene un objeto y devuelve este mismo objeto en formato json, y los tipos de variables del objeto. Por ejemplo: "name": "string"
-// Ejemplo de uso: var json := GetStructTypes(ExampleObject{})
-func GetStructTypes(input interface{}) (string, error) {
-	val := reflect.ValueOf(input)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+// Función para responder sólo con el código de estado, usando su texto estándar (http.StatusText) como mensaje.
+// Para 4xx y 5xx el texto se copia también en el campo error. Los códigos desconocidos usan "Status <código>".
+// 204 y 304 no admiten cuerpo, así que sólo se envía el código de estado (igual que RespondWithNoContent).
+func RespondWithStatus(w http.ResponseWriter, statusCode int) {
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
+		writeHeader(w, statusCode)
+		return
 	}
-	typeOfS := val.Type()
+	text := http.StatusText(statusCode)
+	if text == "" {
+		text = fmt.Sprintf("Status %d", statusCode)
+	}
+	response := NewJsonResponseWithStatus(StatusSuccess, text, nil, "")
+	if statusCode >= 400 {
+		response.Status = StatusError
+		response.Error = text
+	}
+	RespondWithJSON(w, statusCode, response)
+}
 
-	fields := []map[string]string{}
-	for i := 0; i < val.NumField(); i++ {
-		field := typeOfS.Field(i)
-		fieldType := field.Type.String()
+// Función para enviar un 404 con el mensaje en el campo error (por defecto "resource not found")
+func RespondWithNotFound(w http.ResponseWriter, message string) {
+	respondWithStatusMessage(w, http.StatusNotFound, message, "resource not found")
+}
 
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			jsonTag = field.Name
-		} else {
-			jsonTag = strings.Split(jsonTag, ",")[0]
-		}
+// Función para enviar un 401 con el mensaje en el campo error (por defecto "unauthorized")
+func RespondWithUnauthorized(w http.ResponseWriter, message string) {
+	respondWithStatusMessage(w, http.StatusUnauthorized, message, "unauthorized")
+}
+
+// Función para enviar un 403 con el mensaje en el campo error (por defecto "forbidden")
+func RespondWithForbidden(w http.ResponseWriter, message string) {
+	respondWithStatusMessage(w, http.StatusForbidden, message, "forbidden")
+}
+
+// Función para enviar un 400 con el mensaje en el campo error (por defecto "bad request")
+func RespondWithBadRequest(w http.ResponseWriter, message string) {
+	respondWithStatusMessage(w, http.StatusBadRequest, message, "bad request")
+}
 
-		fields = append(fields, map[string]string{jsonTag: fieldType})
+// Función para enviar un 429 cuando el cliente supera el límite de peticiones.
+// La cabecera Retry-After indica en segundos (redondeando hacia arriba) cuándo puede reintentar; con 0 se omite.
+func RespondWithTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
 	}
+	respondWithStatusMessage(w, http.StatusTooManyRequests, "", "too many requests, please retry later")
+}
 
-	fieldTypes := make(map[string]string)
-	for _, field := range fields {
-		for k, v := range field {
-			fieldTypes[k] = v
-		}
+// respondWithStatusMessage envía la respuesta de error con el mensaje, o defaultMessage si está vacío
+func respondWithStatusMessage(w http.ResponseWriter, statusCode int, message, defaultMessage string) {
+	if message == "" {
+		message = defaultMessage
 	}
+	RespondWithError(w, statusCode, errors.New(message))
+}
 
-	jsonData, err := json.MarshalIndent(fieldTypes, "", "  ")
+// Función para enviar un error de validación (422) con el mensaje de cada campo que ha fallado
+func RespondWithValidationError(w http.ResponseWriter, fieldErrors map[string]string) {
+	response := NewJsonResponseWithStatus(StatusError, localizedMessage(MessageKeyError, ""), nil, "validation failed")
+	response.Details = fieldErrors
+	RespondWithJSON(w, http.StatusUnprocessableEntity, response)
+}
+
+// Responder con JSON simple (simplemente data)
+func RespondWithJSONMessageError(w http.ResponseWriter, statusCode int, messageError string) {
+	response := NewJsonResponse("", "", messageError)
+	RespondWithJSON(w, statusCode, response)
+}
+
+// Esta función convierte un objeto (o un modelo de objeto: ej. ExampleModel{}) a un formato JSON
+func ConvertObjectToJSON(obj interface{}) (string, error) {
+	jsonData, err := Marshal(obj)
 	if err != nil {
 		return "", err
 	}
 	return string(jsonData), nil
 }
 
-// Esta función convierte un objeto (o un modelo de objeto: ej. ExampleModel{}) a un formato JSON
-func ConvertObjectToJSON(obj interface{}) (string, error) {
-	jsonData, err := json.Marshal(obj)
+// Igual que ConvertObjectToJSON pero con sangría, útil para logs y depuración
+func ConvertObjectToJSONIndent(obj interface{}, indent string) (string, error) {
+	jsonData, err := marshalIndent(obj, indent)
 	if err != nil {
 		return "", err
 	}
 	return string(jsonData), nil
 }
 
-// ValidateFields comprueba que todos los campos pasados ​​no estén vacíos ni contengan espacios. (string, int)
+// Convierte el objeto a JSON con sangría de dos espacios si pretty es true, o compacto si es false
+func ConvertObjectToJSONPretty(obj interface{}, pretty bool) (string, error) {
+	if pretty {
+		return ConvertObjectToJSONIndent(obj, "  ")
+	}
+	return ConvertObjectToJSON(obj)
+}
+
+// Convierte el objeto a JSON omitiendo las claves indicadas (nombres JSON), sin cambiar las etiquetas de la estructura.
+// Sólo se eliminan las claves del primer nivel; el objeto debe codificarse como un objeto JSON.
+func ConvertObjectToJSONExcept(obj interface{}, exclude ...string) (string, error) {
+	fields, err := objectToMap(obj)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range exclude {
+		delete(fields, key)
+	}
+	return ConvertObjectToJSON(fields)
+}
+
+// objectToMap codifica el objeto y lo decodifica en un mapa, conservando los números tal cual (json.Number)
+func objectToMap(obj interface{}) (map[string]interface{}, error) {
+	jsonData, err := Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil || fields == nil {
+		return nil, errors.New("object must be encoded as a JSON object")
+	}
+	return fields, nil
+}
+
+// ValidateFields comprueba que todos los campos pasados ​​no estén vacíos ni contengan espacios.
+// Admite string, int, uint, float, bool, slice, array, map, time.Time y punteros a ellos.
+// El error indica la posición del campo (desde 0), ej. "field 1: fields cannot be empty or contain spaces".
 func ValidateFields(fields ...interface{}) error {
-	for _, field := range fields {
-		value := reflect.ValueOf(field)
-		switch value.Kind() {
-		case reflect.String:
-			str := value.String()
-			if strings.TrimSpace(str) == "" || value.IsZero() {
-				return fmt.Errorf("fields cannot be empty or contain spaces")
+	for i, field := range fields {
+		if err := validateFieldValue(field); err != nil {
+			return fmt.Errorf("field %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateNamedFields aplica las mismas reglas que ValidateFields a pares nombre→valor para que el error
+// indique el nombre del campo, ej. "field 'username': fields cannot be empty or contain spaces". Los campos se comprueban en orden alfabético.
+func ValidateNamedFields(fields map[string]interface{}) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validateFieldValue(fields[name]); err != nil {
+			return fmt.Errorf("field '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateFieldsAll aplica las mismas reglas que ValidateFields pero acumula todos los fallos.
+// Devuelve nil si todos los campos son válidos, o un ValidationErrors con cada error.
+func ValidateFieldsAll(fields ...interface{}) error {
+	var errs ValidationErrors
+	for i, field := range fields {
+		if err := validateFieldValue(field); err != nil {
+			errs = append(errs, fmt.Errorf("field %d: %w", i, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateStructFields aplica las reglas de ValidateFields a todos los campos exportados de la estructura.
+// Las estructuras anidadas se recorren, también a través de punteros (un puntero nil falla como obligatorio).
+// El error indica la ruta JSON del campo, ej. "field 'address.city': fields cannot be empty or contain spaces".
+func ValidateStructFields(obj interface{}) error {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return errors.New("cannot validate a nil object")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported object type: %s", val.Kind())
+	}
+	return validateStructFields(val, "")
+}
+
+// validateStructFields valida cada campo exportado de la estructura; prefix es la ruta de los campos padre
+func validateStructFields(val reflect.Value, prefix string) error {
+	typeOfS := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typeOfS.Field(i)
+		value := val.Field(i)
+
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			if err := validateStructFields(value, prefix); err != nil {
+				return err
 			}
-		case reflect.Int:
-			if value.Int() == 0 || value.IsZero() {
-				return fmt.Errorf("integer fields cannot be zero")
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		nested := value
+		if nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.Type() != timeType {
+			if err := validateStructFields(nested, name+"."); err != nil {
+				return err
 			}
-		default:
-			return fmt.Errorf("unsupported field type: %s", value.Kind())
+			continue
+		}
+		if err := validateFieldValue(value.Interface()); err != nil {
+			return fmt.Errorf("field '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidationErrors agrupa varios errores de validación en un único error
+type ValidationErrors []error
+
+// Error une los mensajes de cada error con "; "
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap expone los errores individuales para errors.Is y errors.As
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// validateFieldValue comprueba que un único valor no esté vacío según su tipo.
+// El mensaje del error no incluye el campo; quien llama añade la posición o el nombre.
+func validateFieldValue(field interface{}) error {
+	// time.Time es una estructura, por eso se comprueba antes que el tipo de dato
+	if t, ok := field.(time.Time); ok {
+		if t.IsZero() {
+			return errors.New("time fields cannot be zero")
+		}
+		return nil
+	}
+	value := reflect.ValueOf(field)
+	switch value.Kind() {
+	case reflect.String:
+		if strings.TrimSpace(value.String()) == "" {
+			return errors.New("fields cannot be empty or contain spaces")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() == 0 {
+			return errors.New("integer fields cannot be zero")
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() == 0 {
+			return errors.New("integer fields cannot be zero")
+		}
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		if f == 0 || math.IsNaN(f) {
+			return errors.New("float fields cannot be zero or NaN")
+		}
+	case reflect.Bool:
+		// false es un valor válido, los booleanos siempre pasan la validación
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if value.Len() == 0 {
+			return errors.New("collection fields cannot be empty")
+		}
+	case reflect.Ptr:
+		// Un puntero nil indica un campo ausente; si no, se valida el valor apuntado
+		if value.IsNil() {
+			return errors.New("field is required")
 		}
+		return validateFieldValue(value.Elem().Interface())
+	default:
+		return fmt.Errorf("unsupported field type: %s", value.Kind())
 	}
 	return nil
 }
@@ -0,0 +1,134 @@
+package respondwithjson
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// discardWriter es un http.ResponseWriter que descarta la respuesta, para medir sólo el coste de este paquete
+type discardWriter struct {
+	header http.Header
+}
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (d *discardWriter) WriteHeader(int)             {}
+
+func BenchmarkRespondWithJSON(b *testing.B) {
+	w := &discardWriter{header: make(http.Header)}
+	data := map[string]interface{}{"id": 42, "name": "example", "tags": []string{"a", "b", "c"}}
+	response := NewJsonResponseWithStatus(StatusSuccess, SuccessMessage, data, "")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := RespondWithJSON(w, http.StatusOK, response); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMarshalAndWrite es la referencia sin pools (json.Marshal en cada respuesta) para comparar con BenchmarkRespondWithJSON
+func BenchmarkMarshalAndWrite(b *testing.B) {
+	w := &discardWriter{header: make(http.Header)}
+	data := map[string]interface{}{"id": 42, "name": "example", "tags": []string{"a", "b", "c"}}
+	response := NewJsonResponseWithStatus(StatusSuccess, SuccessMessage, data, "")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		body, err := json.Marshal(response)
+		if err != nil {
+			b.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
+
+// nonEscapingEncoder es un Encoder que no escapa el HTML y no expone SetEscapeHTML,
+// como los de algunas librerías JSON alternativas
+type nonEscapingEncoder struct {
+	enc *json.Encoder
+}
+
+func (e nonEscapingEncoder) Encode(v interface{}) error { return e.enc.Encode(v) }
+
+func newNonEscapingEncoder(w io.Writer) Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return nonEscapingEncoder{enc: enc}
+}
+
+func TestRespondWithJSONEscaped(t *testing.T) {
+	const payload = "<script>alert('x') && 1</script>"
+	tests := []struct {
+		name       string
+		newEncoder func(w io.Writer) Encoder
+		respond    func(w http.ResponseWriter, statusCode int, response JsonResponse) error
+	}{
+		{"default encoder RespondWithJSON", nil, RespondWithJSONErr},
+		{"default encoder RespondWithJSONEscaped", nil, RespondWithJSONEscaped},
+		{"non-escaping encoder RespondWithJSONEscaped", newNonEscapingEncoder, RespondWithJSONEscaped},
+		// Un *json.Encoder con el escape desactivado se vuelve a activar explícitamente en cada respuesta
+		{"json.Encoder without escaping RespondWithJSON", func(w io.Writer) Encoder {
+			enc := json.NewEncoder(w)
+			enc.SetEscapeHTML(false)
+			return enc
+		}, RespondWithJSONErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.newEncoder != nil {
+				previous := NewEncoder
+				NewEncoder = tt.newEncoder
+				defer func() { NewEncoder = previous }()
+			}
+
+			rec := httptest.NewRecorder()
+			if err := tt.respond(rec, http.StatusOK, NewJsonResponse("", payload, "")); err != nil {
+				t.Fatal(err)
+			}
+			body := rec.Body.String()
+			for _, raw := range []string{"<", ">", "&"} {
+				if strings.Contains(body, raw) {
+					t.Errorf("body contains unescaped %q: %s", raw, body)
+				}
+			}
+			for _, escaped := range []string{`\u003c`, `\u003e`, `\u0026`} {
+				if !strings.Contains(body, escaped) {
+					t.Errorf("body does not contain %s: %s", escaped, body)
+				}
+			}
+
+			var decoded JsonResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.Data != payload {
+				t.Errorf("data = %q, want %q", decoded.Data, payload)
+			}
+		})
+	}
+}
+
+// TestNonEscapingEncoder comprueba que el encoder de prueba realmente no escapa, para que
+// TestRespondWithJSONEscaped demuestre el escape de RespondWithJSONEscaped y no el del encoder
+func TestNonEscapingEncoder(t *testing.T) {
+	previous := NewEncoder
+	NewEncoder = newNonEscapingEncoder
+	defer func() { NewEncoder = previous }()
+
+	rec := httptest.NewRecorder()
+	if err := RespondWithJSONErr(rec, http.StatusOK, NewJsonResponse("", "<&>", "")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rec.Body.String(), "<&>") {
+		t.Errorf("expected the custom encoder to leave HTML unescaped: %s", rec.Body.String())
+	}
+}
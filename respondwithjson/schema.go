@@ -0,0 +1,417 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDraft07 es el identificador del borrador de JSON Schema que genera GenerateJSONSchema
+const JSONSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// GenerateJSONSchema genera un JSON Schema (draft-07) a partir de una estructura.
+// Los campos sin omitempty se incluyen en el array "required" y las estructuras anidadas se describen recursivamente.
+// Ejemplo de uso: schema, err := GenerateJSONSchema(ExampleObject{})
+func GenerateJSONSchema(input interface{}) (string, error) {
+	typeOfS := reflect.TypeOf(input)
+	for typeOfS != nil && typeOfS.Kind() == reflect.Ptr {
+		typeOfS = typeOfS.Elem()
+	}
+
+	schema := map[string]interface{}{}
+	if typeOfS != nil {
+		schema = schemaForType(typeOfS, map[reflect.Type]bool{})
+	}
+	schema["$schema"] = JSONSchemaDraft07
+
+	jsonData, err := marshalIndent(schema, "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// schemaForType devuelve el esquema de un tipo Go según su equivalente en JSON.
+// Los punteros, slices y mapas admiten además null, que es como encoding/json codifica su valor nil.
+func schemaForType(t reflect.Type, visited map[reflect.Type]bool) map[string]interface{} {
+	schema := schemaForValueType(t, visited)
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if name, ok := schema["type"].(string); ok {
+			schema["type"] = []string{name, "null"}
+		}
+	}
+	return schema
+}
+
+// schemaForValueType devuelve el esquema del tipo sin contar con el valor nil
+func schemaForValueType(t reflect.Type, visited map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if hasCustomMarshaler(t) {
+		// Los tipos con codificación propia pueden producir cualquier valor JSON
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json codifica []byte como un string en base64
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), visited)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), visited)}
+	case reflect.Struct:
+		if visited[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		visited[t] = true
+		defer delete(visited, t)
+
+		properties := map[string]interface{}{}
+		required := []string{}
+		schemaForStruct(t, visited, properties, &required)
+
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	// interface{} y el resto de tipos admiten cualquier valor
+	return map[string]interface{}{}
+}
+
+// schemaForStruct añade a properties y required los campos de la estructura, promocionando los de las estructuras embebidas
+func schemaForStruct(t reflect.Type, visited map[reflect.Type]bool, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				schemaForStruct(embedded, visited, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaForType(field.Type, visited)
+		if !hasTagOption(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// ValidateAgainstSchema comprueba el cuerpo JSON de una petición contra un JSON Schema (por ejemplo el de
+// GenerateJSONSchema) antes de decodificarlo en una estructura. Devuelve un ValidationErrors con todos los fallos.
+// Palabras clave soportadas: type, properties, required, additionalProperties, items, enum, minLength, maxLength,
+// pattern, format (date-time, email, uri), contentEncoding (base64), minimum, maximum, minItems y maxItems.
+// Un esquema que no es JSON válido devuelve un error que envuelve ErrInvalidRule.
+func ValidateAgainstSchema(body []byte, schema string) error {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return fmt.Errorf("%w: invalid JSON schema: %v", ErrInvalidRule, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrEmptyBody
+		}
+		return friendlyDecodeError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("request body must contain a single JSON value")
+	}
+
+	var errs ValidationErrors
+	if err := validateSchemaValue(value, root, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateSchemaValue añade a errs los fallos del valor contra el esquema. Sólo devuelve error si el esquema es inválido
+func validateSchemaValue(value interface{}, schema map[string]interface{}, path string, errs *ValidationErrors) error {
+	if types, ok := schema["type"]; ok {
+		if !matchesSchemaType(value, types) {
+			*errs = append(*errs, fmt.Errorf("%s must be of type %s", schemaSubject(path), schemaTypeNames(types)))
+			return nil
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !inSchemaEnum(value, enum) {
+		*errs = append(*errs, fmt.Errorf("%s must be one of %s", schemaSubject(path), rawJSONList(enum)))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateSchemaObject(v, schema, path, errs)
+	case []interface{}:
+		if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < min {
+			*errs = append(*errs, fmt.Errorf("%s must have at least %s items", schemaSubject(path), formatSchemaNumber(min)))
+		}
+		if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > max {
+			*errs = append(*errs, fmt.Errorf("%s must have at most %s items", schemaSubject(path), formatSchemaNumber(max)))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchemaValue(item, items, fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		return validateSchemaString(v, schema, path, errs)
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be a valid number", schemaSubject(path)))
+			return nil
+		}
+		if min, ok := schemaNumber(schema, "minimum"); ok && n < min {
+			*errs = append(*errs, fmt.Errorf("%s must be at least %s", schemaSubject(path), formatSchemaNumber(min)))
+		}
+		if max, ok := schemaNumber(schema, "maximum"); ok && n > max {
+			*errs = append(*errs, fmt.Errorf("%s must be at most %s", schemaSubject(path), formatSchemaNumber(max)))
+		}
+	}
+	return nil
+}
+
+// validateSchemaObject comprueba required, properties y additionalProperties de un objeto
+func validateSchemaObject(obj map[string]interface{}, schema map[string]interface{}, path string, errs *ValidationErrors) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				return fmt.Errorf("%w: 'required' must contain only strings", ErrInvalidRule)
+			}
+			if _, present := obj[name]; !present {
+				*errs = append(*errs, fmt.Errorf("field '%s' is required", joinSchemaPath(path, name)))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := joinSchemaPath(path, key)
+		if property, ok := properties[key]; ok {
+			propertySchema, ok := property.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%w: schema of property '%s' must be an object", ErrInvalidRule, childPath)
+			}
+			if err := validateSchemaValue(obj[key], propertySchema, childPath, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, fmt.Errorf("field '%s' is not allowed", childPath))
+			}
+		case map[string]interface{}:
+			if err := validateSchemaValue(obj[key], additional, childPath, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaString comprueba minLength, maxLength, pattern, format y contentEncoding de un string
+func validateSchemaString(str string, schema map[string]interface{}, path string, errs *ValidationErrors) error {
+	length := float64(len([]rune(str)))
+	if min, ok := schemaNumber(schema, "minLength"); ok && length < min {
+		*errs = append(*errs, fmt.Errorf("%s must be at least %s characters", schemaSubject(path), formatSchemaNumber(min)))
+	}
+	if max, ok := schemaNumber(schema, "maxLength"); ok && length > max {
+		*errs = append(*errs, fmt.Errorf("%s must be at most %s characters", schemaSubject(path), formatSchemaNumber(max)))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: invalid pattern '%s' for '%s': %v", ErrInvalidRule, pattern, path, err)
+		}
+		if !re.MatchString(str) {
+			*errs = append(*errs, fmt.Errorf("%s must match the pattern '%s'", schemaSubject(path), pattern))
+		}
+	}
+
+	// Los formatos desconocidos se ignoran, como indica la especificación
+	switch schema["format"] {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be a valid date-time (RFC 3339)", schemaSubject(path)))
+		}
+	case "email":
+		if ValidateEmail(str) != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be a valid email address", schemaSubject(path)))
+		}
+	case "uri":
+		if ValidateURL(str) != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be a valid URL", schemaSubject(path)))
+		}
+	}
+	if schema["contentEncoding"] == "base64" {
+		if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be valid base64", schemaSubject(path)))
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType indica si el valor decodificado es de alguno de los tipos JSON Schema indicados (string o array)
+func matchesSchemaType(value interface{}, types interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return matchesSingleSchemaType(value, t)
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && matchesSingleSchemaType(value, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesSingleSchemaType compara el valor con un único tipo JSON Schema
+func matchesSingleSchemaType(value interface{}, name string) bool {
+	switch v := value.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case string:
+		return name == "string"
+	case map[string]interface{}:
+		return name == "object"
+	case []interface{}:
+		return name == "array"
+	case json.Number:
+		if name == "number" {
+			return true
+		}
+		// Según JSON Schema, 1.0 también es un entero
+		f, err := v.Float64()
+		return name == "integer" && err == nil && f == math.Trunc(f)
+	}
+	return false
+}
+
+// schemaTypeNames devuelve los tipos de la palabra clave type para los mensajes de error (ej. "string or null")
+func schemaTypeNames(types interface{}) string {
+	list, ok := types.([]interface{})
+	if !ok {
+		return fmt.Sprint(types)
+	}
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		names = append(names, fmt.Sprint(item))
+	}
+	return strings.Join(names, " or ")
+}
+
+// inSchemaEnum compara el valor con cada opción de enum usando su codificación JSON
+func inSchemaEnum(value interface{}, enum []interface{}) bool {
+	encoded, err := Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, option := range enum {
+		if optionJSON, err := Marshal(option); err == nil && bytes.Equal(encoded, optionJSON) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawJSONList devuelve las opciones de enum codificadas en JSON y separadas por comas
+func rawJSONList(values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		encoded, _ := Marshal(v)
+		parts = append(parts, string(encoded))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// schemaNumber devuelve el valor numérico de una palabra clave del esquema
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+// formatSchemaNumber formatea un número del esquema sin decimales innecesarios
+func formatSchemaNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// joinSchemaPath añade el nombre de una propiedad a la ruta (ej. "address" + "city" = "address.city")
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// schemaSubject devuelve el sujeto de los mensajes de error: el campo o, en la raíz, el cuerpo de la petición
+func schemaSubject(path string) string {
+	if path == "" {
+		return "request body"
+	}
+	return fmt.Sprintf("field '%s'", path)
+}
@@ -0,0 +1,165 @@
+package respondwithjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrFlushNotSupported se devuelve cuando el http.ResponseWriter no implementa http.Flusher
+var ErrFlushNotSupported = errors.New("response writer does not support flushing")
+
+// SSEWriter envía eventos Server-Sent Events con los datos codificados en JSON
+type SSEWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// Constructor para SSEWriter. Escribe las cabeceras text/event-stream y devuelve
+// ErrFlushNotSupported si el writer no permite enviar los eventos a medida que se generan.
+func NewSSEWriter(w http.ResponseWriter) (*SSEWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, ErrFlushNotSupported
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	writeHeader(w, http.StatusOK)
+	flusher.Flush()
+	return &SSEWriter{w: w, flusher: flusher}, nil
+}
+
+// SendEvent codifica data en JSON y lo envía como un evento. Si event está vacío se omite la línea "event:"
+func (s *SSEWriter) SendEvent(event string, data interface{}) error {
+	if strings.ContainsAny(event, "\r\n") {
+		return errors.New("event name cannot contain line breaks")
+	}
+	jsonData, err := Marshal(data)
+	if err != nil {
+		return err
+	}
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", jsonData); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// streamFlushInterval es el número de elementos tras el que StreamJSONArray envía los datos al cliente
+const streamFlushInterval = 100
+
+// StreamErrorTrailer es el trailer HTTP con el que StreamJSONArray indica que el flujo terminó por un error
+const StreamErrorTrailer = "X-Stream-Error"
+
+// StreamJSONArray escribe {"data":[...]} codificando los elementos a medida que llegan por el canal,
+// sin mantener el slice completo en memoria. Termina cuando se cierra el canal.
+// Si por el canal llega un error, o falla la codificación de un elemento, deja de leer del canal, cierra el array
+// y envía el mensaje en el trailer X-Stream-Error (declarado en la cabecera Trailer) para que el cliente sepa que
+// la respuesta está incompleta. Como el código de estado ya se ha enviado, el error devuelto sólo sirve para registrarlo.
+func StreamJSONArray(w http.ResponseWriter, statusCode int, items <-chan interface{}) error {
+	flusher, _ := w.(http.Flusher)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Trailer", StreamErrorTrailer)
+	writeHeader(w, statusCode)
+	if _, err := io.WriteString(w, `{"data":[`); err != nil {
+		return err
+	}
+
+	encoder := NewEncoder(w)
+	count := 0
+	var streamErr error
+	for item := range items {
+		if err, ok := item.(error); ok {
+			streamErr = err
+			break
+		}
+		if count > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		// json.Encoder codifica en memoria antes de escribir, así que un fallo no deja el elemento a medias
+		if err := encoder.Encode(item); err != nil {
+			streamErr = err
+			break
+		}
+		count++
+		if flusher != nil && count%streamFlushInterval == 0 {
+			flusher.Flush()
+		}
+	}
+
+	if _, err := io.WriteString(w, "]}\n"); err != nil {
+		return err
+	}
+	if streamErr != nil {
+		setStreamError(w, streamErr)
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return streamErr
+}
+
+// setStreamError envía el error en el trailer X-Stream-Error; los valores de las cabeceras no pueden contener saltos de línea
+func setStreamError(w http.ResponseWriter, err error) {
+	w.Header().Set(StreamErrorTrailer, strings.NewReplacer("\r", " ", "\n", " ").Replace(err.Error()))
+}
+
+// StreamNDJSON escribe los elementos del canal en formato NDJSON (application/x-ndjson): un objeto JSON compacto
+// por línea, enviando cada uno al cliente en cuanto se escribe. Termina cuando se cierra el canal.
+// Igual que en StreamJSONArray, un error recibido por el canal detiene el flujo y se envía en el trailer X-Stream-Error.
+func StreamNDJSON(w http.ResponseWriter, items <-chan interface{}) error {
+	return StreamNDJSONContext(context.Background(), w, items)
+}
+
+// Igual que StreamNDJSON pero deja de escribir y devuelve ctx.Err() si se cancela el contexto (ej. r.Context()
+// cuando el cliente se desconecta). También se detiene en el primer error de escritura o de flush.
+func StreamNDJSONContext(ctx context.Context, w http.ResponseWriter, items <-chan interface{}) error {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Trailer", StreamErrorTrailer)
+	writeHeader(w, http.StatusOK)
+
+	for {
+		var item interface{}
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok = <-items:
+		}
+		if !ok {
+			return nil
+		}
+
+		if itemErr, isErr := item.(error); isErr {
+			setStreamError(w, itemErr)
+			return itemErr
+		}
+		line, err := Marshal(item)
+		if err != nil {
+			setStreamError(w, err)
+			return err
+		}
+
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		// Si el writer no admite flush se sigue escribiendo; net/http enviará los datos al llenar su buffer
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package respondwithjson
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy convierte el nombre Go de un campo sin etiqueta json en su nombre JSON (ej. SnakeCase, CamelCase)
+type NamingStrategy func(fieldName string) string
+
+// DefaultStructTypesMaxDepth es el número máximo de niveles de estructuras anidadas que describe GetStructTypes
+const DefaultStructTypesMaxDepth = 10
+
+// StructTypesOptions son las opciones de GetStructTypesWithOptions
+type StructTypesOptions struct {
+	Simple   bool           // Salida plana de versiones anteriores: "name": "string", sin indicar si el campo es obligatorio
+	Naming   NamingStrategy // Nombre de los campos sin etiqueta json (nil deja el nombre Go)
+	MaxDepth int            // Niveles de estructuras anidadas a describir; 0 usa DefaultStructTypesMaxDepth
+}
+
+// Esta función obtiene un objeto y devuelve este mismo objeto en formato json, con el tipo de cada campo y si es
+// obligatorio (sin omitempty) u opcional. Por ejemplo: "name": {"type": "string", "required": true}
+// Las estructuras anidadas se describen en "fields" y los slices de estructuras en "items".
+// Para la salida plana ("name": "string") usar GetStructTypesWithOptions con Simple: true.
+// Ejemplo de uso: var json := GetStructTypes(ExampleObject{})
+func GetStructTypes(input interface{}) (string, error) {
+	return GetStructTypesWithOptions(input, StructTypesOptions{})
+}
+
+// Igual que GetStructTypes pero aplica naming al nombre de los campos sin etiqueta json,
+// para que coincida con lo que produce un marshaler configurado con esa convención.
+// Ejemplo de uso: var json := GetStructTypesWithNaming(ExampleObject{}, CamelCase)
+func GetStructTypesWithNaming(input interface{}, naming NamingStrategy) (string, error) {
+	return GetStructTypesWithOptions(input, StructTypesOptions{Naming: naming})
+}
+
+// Igual que GetStructTypes pero con las opciones indicadas.
+// Ejemplo de uso: var json := GetStructTypesWithOptions(ExampleObject{}, StructTypesOptions{Simple: true})
+func GetStructTypesWithOptions(input interface{}, opts StructTypesOptions) (string, error) {
+	return describeInput(input, &structDescriber{typeName: goTypeName, naming: opts.Naming, detailed: !opts.Simple, maxDepth: opts.MaxDepth})
+}
+
+// Igual que GetStructTypes pero con los tipos JSON en lugar de los tipos Go. Por ejemplo: "age": "number".
+// time.Time se describe como "string (format: date-time)".
+// Ejemplo de uso: var json := GetStructJSONTypes(ExampleObject{})
+func GetStructJSONTypes(input interface{}) (string, error) {
+	return describeInput(input, &structDescriber{typeName: jsonTypeName})
+}
+
+// describeInput describe la estructura (o puntero a estructura) y devuelve el resultado en JSON con sangría
+func describeInput(input interface{}, d *structDescriber) (string, error) {
+	typeOfS := reflect.TypeOf(input)
+	if typeOfS.Kind() == reflect.Ptr {
+		typeOfS = typeOfS.Elem()
+	}
+
+	if d.maxDepth <= 0 {
+		d.maxDepth = DefaultStructTypesMaxDepth
+	}
+	d.depth = 1
+	d.visited = map[reflect.Type]bool{typeOfS: true}
+	fieldTypes := d.describeStruct(typeOfS)
+
+	jsonData, err := marshalIndent(fieldTypes, "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// structDescriber contiene las opciones para describir los tipos de una estructura
+type structDescriber struct {
+	typeName func(reflect.Type) string // Descripción de los tipos que no son estructuras
+	naming   NamingStrategy            // Nombre de los campos sin etiqueta json (nil deja el nombre Go)
+	visited  map[reflect.Type]bool     // Estructuras que se están describiendo, para cortar las referencias cíclicas
+	detailed bool                      // Describir cada campo con su tipo y si es obligatorio
+	depth    int                       // Nivel de anidamiento actual (la estructura raíz es el nivel 1)
+	maxDepth int                       // A partir de este nivel las estructuras se sustituyen por truncatedFields
+}
+
+// truncatedFields sustituye a las estructuras que superan la profundidad máxima
+var truncatedFields = orderedFields{{Key: "...", Value: "truncated"}}
+
+// orderedField es un par nombre JSON / descripción del tipo de un campo
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// orderedFields se codifica como un objeto JSON manteniendo el orden de declaración de los campos,
+// para que la salida sea estable (un mapa se codificaría en otro orden)
+type orderedFields []orderedField
+
+// MarshalJSON codifica los campos en orden
+func (f orderedFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// describeStruct devuelve el nombre JSON de cada campo y la descripción de su tipo, en el orden de declaración.
+// Igual que en GenerateJSONSchema, se omiten los campos con json:"-" y los no exportados, que nunca se codifican,
+// y se promocionan los campos de las estructuras embebidas sin nombre.
+func (d *structDescriber) describeStruct(typeOfS reflect.Type) orderedFields {
+	fieldTypes := make(orderedFields, 0, typeOfS.NumField())
+	d.describeFields(typeOfS, &fieldTypes)
+	return fieldTypes
+}
+
+// describeFields añade a out la descripción de los campos de la estructura
+func (d *structDescriber) describeFields(typeOfS reflect.Type, out *orderedFields) {
+	for i := 0; i < typeOfS.NumField(); i++ {
+		field := typeOfS.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !d.visited[embedded] {
+				d.visited[embedded] = true
+				d.describeFields(embedded, out)
+				delete(d.visited, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+			if d.naming != nil {
+				name = d.naming(field.Name)
+			}
+		}
+
+		var description interface{} = d.describeType(field.Type)
+		if d.detailed {
+			description = d.describeField(field.Type, description, !hasTagOption(opts, "omitempty"))
+		}
+		out.set(name, description)
+	}
+}
+
+// describeField describe un campo en el modo detallado: su tipo, si es obligatorio y, para las estructuras
+// y los slices de estructuras, la descripción de sus campos (fields) o de sus elementos (items)
+func (d *structDescriber) describeField(t reflect.Type, description interface{}, required bool) orderedFields {
+	entry := orderedFields{
+		{Key: "type", Value: d.typeName(t)},
+		{Key: "required", Value: required},
+	}
+	switch nested := description.(type) {
+	case orderedFields:
+		entry = append(entry, orderedField{Key: "fields", Value: nested})
+	case []interface{}:
+		entry = append(entry, orderedField{Key: "items", Value: nested[0]})
+	}
+	return entry
+}
+
+// describeType describe un tipo: las estructuras como objetos, los slices de estructuras como arrays
+// y el resto con typeName. Los tipos ya visitados (referencias cíclicas) se describen con typeName.
+func (d *structDescriber) describeType(t reflect.Type) interface{} {
+	elem := t
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		if d.visited[elem] || hasCustomMarshaler(elem) {
+			return d.typeName(t)
+		}
+		if d.depth >= d.maxDepth {
+			return truncatedFields
+		}
+		d.visited[elem] = true
+		d.depth++
+		defer func() {
+			delete(d.visited, elem)
+			d.depth--
+		}()
+		return d.describeStruct(elem)
+	case reflect.Slice, reflect.Array:
+		item := elem.Elem()
+		for item.Kind() == reflect.Ptr {
+			item = item.Elem()
+		}
+		if item.Kind() == reflect.Struct && !hasCustomMarshaler(item) {
+			return []interface{}{d.describeType(elem.Elem())}
+		}
+	}
+	return d.typeName(t)
+}
+
+// SnakeCase convierte un nombre Go en snake_case (ej. "UserID" -> "user_id")
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// CamelCase convierte un nombre Go en camelCase (ej. "UserName" -> "userName", "ID" -> "id", "HTTPServer" -> "httpServer")
+func CamelCase(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	switch {
+	case upper == 0:
+		return name
+	case upper == len(runes):
+		return strings.ToLower(name)
+	case upper > 1:
+		// En un acrónimo seguido de otra palabra, la última mayúscula es el inicio de esa palabra
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// goTypeName devuelve el nombre del tipo Go (ej. "int64", "time.Time")
+func goTypeName(t reflect.Type) string {
+	return t.String()
+}
+
+// jsonTypeName devuelve el tipo JSON equivalente al tipo Go (string, number, boolean, array, object)
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "string (format: date-time)"
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return "string"
+	}
+	if hasCustomMarshaler(t) {
+		return "any"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "any"
+}
+
+// hasCustomMarshaler indica si el tipo define su propia codificación JSON o de texto (ej. time.Time)
+func hasCustomMarshaler(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)
+}
@@ -0,0 +1,176 @@
+package respondwithjson
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TimeFormat indica cómo se codifican los valores time.Time
+type TimeFormat int
+
+const (
+	TimeRFC3339     TimeFormat = iota // String RFC 3339 (por defecto, igual que encoding/json)
+	TimeUnixSeconds                   // Número de segundos desde el epoch Unix
+	TimeUnixMillis                    // Número de milisegundos desde el epoch Unix
+)
+
+// EncodeOptions son las opciones de codificación por llamada de ConvertObjectToJSONWithOptions y RespondWithJSONOptions.
+// El valor cero codifica igual que ConvertObjectToJSON y RespondWithJSON.
+type EncodeOptions struct {
+	TimeFormat TimeFormat
+}
+
+// Igual que ConvertObjectToJSON pero aplicando las opciones (ej. los time.Time como milisegundos Unix)
+func ConvertObjectToJSONWithOptions(obj interface{}, opts EncodeOptions) (string, error) {
+	return ConvertObjectToJSON(opts.apply(obj))
+}
+
+// Igual que RespondWithJSON pero aplicando las opciones a Data y Meta. Data se normaliza antes, así que un slice nil
+// se sigue codificando como [].
+// Ejemplo: RespondWithJSONOptions(w, http.StatusOK, response, EncodeOptions{TimeFormat: TimeUnixMillis})
+func RespondWithJSONOptions(w http.ResponseWriter, statusCode int, response JsonResponse, opts EncodeOptions) error {
+	response.Data = opts.apply(normalizeData(response.Data))
+	if response.Meta != nil {
+		meta := make(map[string]interface{}, len(response.Meta))
+		for key, value := range response.Meta {
+			meta[key] = opts.apply(value)
+		}
+		response.Meta = meta
+	}
+	return RespondWithJSONErr(w, statusCode, response)
+}
+
+// apply devuelve el objeto listo para json.Marshal con las opciones aplicadas; sin opciones lo devuelve tal cual
+func (o EncodeOptions) apply(obj interface{}) interface{} {
+	if o.TimeFormat == TimeRFC3339 {
+		return obj
+	}
+	return o.rewriteTimes(reflect.ValueOf(obj))
+}
+
+// formatTime codifica el time.Time según TimeFormat
+func (o EncodeOptions) formatTime(t time.Time) interface{} {
+	switch o.TimeFormat {
+	case TimeUnixSeconds:
+		return t.Unix()
+	case TimeUnixMillis:
+		return t.UnixMilli()
+	}
+	return t
+}
+
+// rewriteTimes devuelve una copia del valor, apta para json.Marshal, con los time.Time codificados según las opciones.
+// Las estructuras se copian respetando las etiquetas json y el orden de los campos.
+func (o EncodeOptions) rewriteTimes(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == timeType {
+		return o.formatTime(v.Interface().(time.Time))
+	}
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == timeType {
+		if v.IsNil() {
+			return nil
+		}
+		return o.formatTime(v.Elem().Interface().(time.Time))
+	}
+	// El resto de tipos con su propio marshaler se codifican tal cual
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil
+		}
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return o.rewriteTimes(v.Elem())
+	case reflect.Struct:
+		var out orderedFields
+		o.rewriteStruct(v, &out)
+		if out == nil {
+			out = orderedFields{}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = o.rewriteTimes(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = o.rewriteTimes(iter.Value())
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// rewriteStruct añade a out los campos exportados de la estructura con sus nombres JSON,
+// promocionando los de las estructuras embebidas sin nombre
+func (o EncodeOptions) rewriteStruct(v reflect.Value, out *orderedFields) {
+	typeOfS := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := typeOfS.Field(i)
+		value := v.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded.Type() != timeType {
+				o.rewriteStruct(embedded, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if hasTagOption(opts, "omitempty") && isEmptyJSONValue(value) {
+			continue
+		}
+		out.set(name, o.rewriteTimes(value))
+	}
+}
+
+// set añade el campo o sustituye su valor si ya existe (ej. un campo que oculta otro de una estructura embebida)
+func (f *orderedFields) set(key string, value interface{}) {
+	for i := range *f {
+		if (*f)[i].Key == key {
+			(*f)[i].Value = value
+			return
+		}
+	}
+	*f = append(*f, orderedField{Key: key, Value: value})
+}
@@ -0,0 +1,42 @@
+package respondwithjson
+
+import "net/http"
+
+// TypedResponse es la versión genérica de JsonResponse con el campo Data tipado.
+// Produce el mismo JSON que JsonResponse (message/data/error/timestamp).
+type TypedResponse[T any] struct {
+	Status    string `json:"status,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Data      T      `json:"data,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// MarshalJSON codifica la respuesta igual que JsonResponse, con la misma normalización de Data
+// (ej. un slice nil se codifica como "data":[])
+func (r TypedResponse[T]) MarshalJSON() ([]byte, error) {
+	return JsonResponse{
+		Status:    r.Status,
+		Message:   r.Message,
+		Data:      r.Data,
+		Error:     r.Error,
+		Timestamp: r.Timestamp,
+	}.MarshalJSON()
+}
+
+// Constructor para la respuesta TypedResponse
+func NewTypedResponse[T any](message string, data T, err string) TypedResponse[T] {
+	return TypedResponse[T]{
+		Message: message,
+		Data:    data,
+		Error:   err,
+	}
+}
+
+// Responder con JSON tipado (simplemente data), equivalente a RespondWithJSONSimple
+func RespondWithTyped[T any](w http.ResponseWriter, statusCode int, data T) error {
+	response := NewTypedResponse("", data, "")
+	response.Timestamp = stampTimestamp(response.Timestamp)
+	_, err := writeJSON(w, statusCode, response)
+	return err
+}
@@ -0,0 +1,76 @@
+package respondwithjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ContextKey es el tipo de las claves de contexto de este paquete, para no colisionar con las de otros paquetes
+type ContextKey string
+
+// UserIDContextKey es la clave con la que RespondWithJSONUser lee el identificador del usuario del contexto.
+// Se puede sustituir por la clave que use el middleware de autenticación de la aplicación.
+var UserIDContextKey interface{} = ContextKey("user_id")
+
+// UserObserver, si no es nil, se llama en cada respuesta de RespondWithJSONUser con el código de estado enviado
+// y el identificador del usuario (vacío si el contexto no lo tiene). Sirve para ligar los logs de acceso al usuario,
+// también en las respuestas correctas, que no pasan por ErrorLogger; ResponseObserver sólo recibe el código de estado
+// y cambiar su firma rompería a quien ya lo usa.
+var UserObserver func(statusCode int, userID string)
+
+// UserError envuelve un error con el usuario que hizo la petición; es el error que recibe ErrorLogger
+// desde RespondWithJSONUser y se puede obtener con errors.As
+type UserError struct {
+	UserID string
+	Err    error
+}
+
+// Error devuelve el mensaje del error precedido del usuario
+func (e UserError) Error() string {
+	return fmt.Sprintf("user %s: %v", e.UserID, e.Err)
+}
+
+// Unwrap devuelve el error envuelto para errors.Is y errors.As
+func (e UserError) Unwrap() error {
+	return e.Err
+}
+
+// UserIDFromContext devuelve el identificador del usuario guardado en el contexto con UserIDContextKey
+func UserIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	switch id := ctx.Value(UserIDContextKey).(type) {
+	case string:
+		return id
+	case fmt.Stringer:
+		return id.String()
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// Igual que RespondWithJSON pero pasa el identificador del usuario del contexto (ej. r.Context()) a UserObserver
+// y, en las respuestas de error (código >= 400 o campo error) o si falla la codificación, a ErrorLogger dentro de un UserError.
+func RespondWithJSONUser(ctx context.Context, w http.ResponseWriter, statusCode int, response JsonResponse) error {
+	userID := UserIDFromContext(ctx)
+	_, err := RespondWithJSON(w, statusCode, response)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		logError(statusCode, UserError{UserID: userID, Err: err})
+	} else if statusCode >= 400 || response.Error != "" {
+		message := response.Error
+		if message == "" {
+			message = http.StatusText(statusCode)
+		}
+		logError(statusCode, UserError{UserID: userID, Err: errors.New(message)})
+	}
+	if UserObserver != nil {
+		UserObserver(statusCode, userID)
+	}
+	return err
+}
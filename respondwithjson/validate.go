@@ -0,0 +1,425 @@
+package respondwithjson
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidRule indica un error del desarrollador en la etiqueta `validate` (regla desconocida, patrón inválido...),
+// no un fallo de validación de los datos
+var ErrInvalidRule = errors.New("invalid validation rule")
+
+// regexCache guarda los patrones de la regla regex ya compilados
+var regexCache sync.Map
+
+// Validate recorre los campos de la estructura y comprueba las reglas indicadas en la etiqueta `validate`.
+// Reglas soportadas: required, min=N y max=N (longitud para strings, valor para números), email, url y
+// regex=PATRÓN (debe ir la última). Las reglas mal escritas devuelven un error que envuelve ErrInvalidRule.
+// El modificador allowzero hace que required acepte el valor 0 en los campos numéricos: en un puntero
+// (ej. Count *int `validate:"required,allowzero"`) sólo falla si es nil, es decir, si el campo no se ha enviado;
+// en un campo que no es puntero no se puede distinguir la ausencia del 0, así que required siempre se cumple.
+// Recorre también las estructuras embebidas y omite los campos no exportados.
+// Si algún campo no es válido devuelve un ValidationErrors con un ValidationError por cada campo que falla.
+// Ejemplo: Email string `json:"email" validate:"required,min=3"`
+func Validate(obj interface{}) error {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return errors.New("cannot validate a nil object")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported object type: %s", val.Kind())
+	}
+	var errs ValidationErrors
+	if err := validateStruct(val, &errs); err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidationError es el fallo de una regla de validación en un campo. Permite construir respuestas por campo
+// (ver RespondWithValidationErrors) y comprobar la regla concreta con errors.As.
+type ValidationError struct {
+	Field   string // Nombre JSON del campo
+	Rule    string // Regla que ha fallado (required, min, max, email, url, regex...)
+	Message string // Mensaje que se puede mostrar al cliente
+}
+
+// Error devuelve el mensaje del fallo
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// FieldErrors devuelve los ValidationError contenidos en el error (ej. el que devuelve Validate), o nil si no hay ninguno
+func FieldErrors(err error) []ValidationError {
+	var fieldErrs []ValidationError
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			fieldErrs = append(fieldErrs, FieldErrors(e)...)
+		}
+		return fieldErrs
+	}
+	var fieldErr ValidationError
+	if errors.As(err, &fieldErr) {
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	return fieldErrs
+}
+
+// validateStruct aplica las reglas de validación a cada campo de la estructura y añade los fallos a errs.
+// Sólo devuelve error si una regla está mal escrita (ErrInvalidRule).
+func validateStruct(val reflect.Value, errs *ValidationErrors) error {
+	typeOfS := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typeOfS.Field(i)
+		value := val.Field(i)
+
+		if field.Anonymous {
+			embedded := value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := validateStruct(embedded, errs); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if err := validateField(fieldName(field), value, tag); err != nil {
+			var fieldErr ValidationError
+			if !errors.As(err, &fieldErr) {
+				return err
+			}
+			*errs = append(*errs, err)
+		}
+	}
+	return nil
+}
+
+// fieldName devuelve el nombre JSON del campo, o el nombre Go si no tiene etiqueta json
+func fieldName(field reflect.StructField) string {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "" || jsonTag == "-" {
+		return field.Name
+	}
+	if name := strings.Split(jsonTag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// splitRules separa las reglas de la etiqueta `validate` por comas, ignorando los espacios alrededor.
+// La regla regex= debe ir la última porque su patrón puede contener comas (ej. {3,20}).
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if strings.HasPrefix(tag, "regex") && strings.HasPrefix(strings.TrimLeft(tag[len("regex"):], " "), "=") {
+			return append(rules, tag)
+		}
+		rule, rest, _ := strings.Cut(tag, ",")
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+		tag = rest
+	}
+	return rules
+}
+
+// validateField aplica al valor las reglas de la etiqueta `validate`.
+// Las reglas min y max se comprueban juntas al final para poder indicar el rango completo.
+func validateField(name string, value reflect.Value, tag string) error {
+	var minParam, maxParam string
+	var hasMin, hasMax bool
+	rules := splitRules(tag)
+	allowZero := false
+	for _, rule := range rules {
+		if strings.TrimSpace(rule) == "allowzero" {
+			allowZero = true
+		}
+	}
+	for _, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+		key = strings.TrimSpace(key)
+		if key != "regex" {
+			param = strings.TrimSpace(param)
+		}
+		switch key {
+		case "allowzero":
+			// Modificador de required, ya procesado
+		case "required":
+			if allowZero && isNumericValue(value) {
+				continue
+			}
+			if isEmptyValue(value) {
+				return ValidationError{Field: name, Rule: key, Message: fmt.Sprintf("field '%s' is required", name)}
+			}
+		case "min":
+			minParam, hasMin = param, true
+		case "max":
+			maxParam, hasMax = param, true
+		case "email":
+			if str, ok := stringValue(value); ok && str != "" && ValidateEmail(str) != nil {
+				return ValidationError{Field: name, Rule: key, Message: fmt.Sprintf("field '%s' must be a valid email address", name)}
+			}
+		case "url":
+			if str, ok := stringValue(value); ok && str != "" && ValidateURL(str) != nil {
+				return ValidationError{Field: name, Rule: key, Message: fmt.Sprintf("field '%s' must be a valid URL", name)}
+			}
+		case "regex":
+			if err := validateRegex(name, value, param); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: unknown validation rule '%s' on field '%s'", ErrInvalidRule, key, name)
+		}
+	}
+	if hasMin || hasMax {
+		return validateRange(name, value, minParam, hasMin, maxParam, hasMax)
+	}
+	return nil
+}
+
+// ValidateEmail comprueba que el string sea una dirección de email válida (ej. user@example.com)
+func ValidateEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || !strings.Contains(s, "@") {
+		return fmt.Errorf("invalid email address: %q", s)
+	}
+	return nil
+}
+
+// ValidateURL comprueba que el string sea una URL absoluta válida (con esquema y host)
+func ValidateURL(s string) error {
+	u, err := url.ParseRequestURI(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL: %q", s)
+	}
+	return nil
+}
+
+// stringValue devuelve el valor si es un string (o un puntero no nil a string)
+func stringValue(value reflect.Value) (string, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.String {
+		return "", false
+	}
+	return value.String(), true
+}
+
+// isNumericValue indica si el valor es un número (o un puntero no nil a un número)
+func isNumericValue(value reflect.Value) bool {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isEmptyValue indica si el valor se considera vacío para la regla required
+func isEmptyValue(value reflect.Value) bool {
+	if value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) == ""
+	}
+	return value.IsZero()
+}
+
+// validateRange comprueba la longitud de un string o el valor de un número contra los límites min y max
+func validateRange(name string, value reflect.Value, minParam string, hasMin bool, maxParam string, hasMax bool) error {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	var min, max float64
+	var err error
+	if hasMin {
+		if min, err = strconv.ParseFloat(minParam, 64); err != nil {
+			return fmt.Errorf("%w: invalid min value '%s' on field '%s'", ErrInvalidRule, minParam, name)
+		}
+	}
+	if hasMax {
+		if max, err = strconv.ParseFloat(maxParam, 64); err != nil {
+			return fmt.Errorf("%w: invalid max value '%s' on field '%s'", ErrInvalidRule, maxParam, name)
+		}
+	}
+
+	var n float64
+	unit := ""
+	switch value.Kind() {
+	case reflect.String:
+		n = float64(len([]rune(value.String())))
+		unit = " characters"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return fmt.Errorf("%w: rules 'min'/'max' are not supported on field '%s' of type %s", ErrInvalidRule, name, value.Kind())
+	}
+
+	if (hasMin && n < min) || (hasMax && n > max) {
+		rule := "max"
+		if hasMin && n < min {
+			rule = "min"
+		}
+		var message string
+		switch {
+		case hasMin && hasMax:
+			message = fmt.Sprintf("field '%s' must be between %s and %s%s", name, minParam, maxParam, unit)
+		case hasMin:
+			message = fmt.Sprintf("field '%s' must be at least %s%s", name, minParam, unit)
+		default:
+			message = fmt.Sprintf("field '%s' must be at most %s%s", name, maxParam, unit)
+		}
+		return ValidationError{Field: name, Rule: rule, Message: message}
+	}
+	return nil
+}
+
+// validateRegex comprueba que el string, si no está vacío, cumpla el patrón (la presencia se comprueba con required).
+// Los patrones compilados se guardan en regexCache.
+func validateRegex(name string, value reflect.Value, pattern string) error {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return fmt.Errorf("%w: invalid regex '%s' on field '%s': %v", ErrInvalidRule, pattern, name, err)
+	}
+
+	str, ok := stringValue(value)
+	if !ok {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil
+		}
+		return fmt.Errorf("%w: rule 'regex' is not supported on field '%s' of type %s", ErrInvalidRule, name, value.Kind())
+	}
+	if str != "" && !re.MatchString(str) {
+		return ValidationError{Field: name, Rule: "regex", Message: fmt.Sprintf("field '%s' must match the pattern '%s'", name, pattern)}
+	}
+	return nil
+}
+
+// compileRegex compila el patrón o lo devuelve de regexCache si ya se compiló antes
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// ValidateUTF8 recorre los campos string del objeto (incluidos los de estructuras anidadas, punteros, slices y mapas)
+// y devuelve un error con el nombre del primer campo que contiene UTF-8 inválido, ej. "field 'user.name' contains invalid UTF-8".
+// encoding/json sustituye las secuencias inválidas por U+FFFD al decodificar, así que es útil sobre todo para
+// datos que llegan por otras vías (formularios, query, cabeceras) o decodificados con otros paquetes.
+func ValidateUTF8(obj interface{}) error {
+	return validateUTF8Value(reflect.ValueOf(obj), "")
+}
+
+// validateUTF8Value comprueba los strings del valor; path es la ruta del campo para el mensaje de error
+func validateUTF8Value(value reflect.Value, path string) error {
+	switch value.Kind() {
+	case reflect.String:
+		if !utf8.ValidString(value.String()) {
+			if path == "" {
+				return errors.New("value contains invalid UTF-8")
+			}
+			return fmt.Errorf("field '%s' contains invalid UTF-8", path)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			return validateUTF8Value(value.Elem(), path)
+		}
+	case reflect.Struct:
+		typeOfS := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := typeOfS.Field(i)
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			// Los campos de las estructuras embebidas se promocionan, como en JSON
+			embedded := field.Anonymous && fieldType.Kind() == reflect.Struct
+			if !field.IsExported() && !embedded {
+				continue
+			}
+			fieldPath := path
+			if !embedded {
+				fieldPath = joinSchemaPath(path, fieldName(field))
+			}
+			if err := validateUTF8Value(value.Field(i), fieldPath); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateUTF8Value(value.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			var key string
+			switch k := iter.Key(); {
+			case k.Kind() == reflect.String:
+				key = k.String()
+				if !utf8.ValidString(key) {
+					return fmt.Errorf("field '%s' contains a key with invalid UTF-8", path)
+				}
+			case k.CanInterface():
+				key = fmt.Sprint(k.Interface())
+			}
+			if err := validateUTF8Value(iter.Value(), joinSchemaPath(path, key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package respondwithyaml responde con YAML usando el mismo envoltorio que respondwithjson.
+// Está separado de respondwithjson para no añadir la dependencia de YAML a quien sólo necesita JSON.
+// Los datos se codifican según sus etiquetas yaml (no las json).
+package respondwithyaml
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rgonzalezNetel/rlib/respondwithjson"
+	"gopkg.in/yaml.v3"
+)
+
+// Responder con YAML simple (simplemente data), equivalente a RespondWithJSONSimple
+func RespondWithYAML(w http.ResponseWriter, statusCode int, data interface{}) error {
+	response := respondwithjson.NewJsonResponse("", data, "")
+	return RespondWithYAMLResponse(w, statusCode, response)
+}
+
+// Responder con el envoltorio JsonResponse codificado en YAML (application/x-yaml).
+// La respuesta se codifica antes de enviar el código de estado; si falla se responde 500.
+func RespondWithYAMLResponse(w http.ResponseWriter, statusCode int, response respondwithjson.JsonResponse) error {
+	if respondwithjson.IncludeTimestamp && response.Timestamp == "" {
+		response.Timestamp = respondwithjson.Now().UTC().Format(time.RFC3339)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(response); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(statusCode)
+	if respondwithjson.ResponseObserver != nil {
+		respondwithjson.ResponseObserver(statusCode)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}